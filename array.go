@@ -0,0 +1,457 @@
+package jpath
+
+import (
+	"encoding/json"
+)
+
+// Chunk splits the underlying array into consecutive arrays of at most size elements, each
+// wrapped in a JPath. The final chunk holds any remainder. If size is not positive, or the
+// underlying value is not an array, an empty slice is returned.
+func (jp JPath) Chunk(size int) []JPath {
+	ret := []JPath{}
+	ary, ok := jp.I.([]interface{})
+
+	if !ok || size <= 0 {
+		return ret
+	}
+
+	for start := 0 ; start < len(ary) ; start += size {
+		end := start + size
+		if end > len(ary) {
+			end = len(ary)
+		}
+
+		chunk := make([]interface{}, end - start)
+		copy(chunk, ary[start:end])
+		ret = append(ret, JPath{I: chunk})
+	}
+
+	return ret
+}
+
+// jsonSet is a set of values compared by JSON-value equality. Values are bucketed by their
+// Normalize'd compact JSON, in which equal values encode identically, and only compared with
+// jsonEqual against the other members of their bucket. Lookups therefore take roughly constant
+// time rather than scanning every member.
+type jsonSet map[string][]interface{}
+
+// jsonSetOf returns a jsonSet holding the elements of ary.
+func jsonSetOf(ary []interface{}) jsonSet {
+	set := jsonSet{}
+
+	for _, elem := range ary {
+		set.add(elem)
+	}
+
+	return set
+}
+
+// jsonSetKey returns the bucket for v. Values which can't be marshaled share the "" bucket.
+func jsonSetKey(v interface{}) string {
+	bytes, _ := json.Marshal(finiteFloats(normalize(v)))
+	return string(bytes)
+}
+
+// contains reports whether set holds a value equal to v.
+func (set jsonSet) contains(v interface{}) bool {
+	for _, member := range set[jsonSetKey(v)] {
+		if jsonEqual(member, v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// add adds v to set, reporting whether it was missing beforehand.
+func (set jsonSet) add(v interface{}) bool {
+	key := jsonSetKey(v)
+
+	for _, member := range set[key] {
+		if jsonEqual(member, v) {
+			return false
+		}
+	}
+
+	set[key] = append(set[key], v)
+	return true
+}
+
+// DistinctCount returns the number of unique elements in the underlying array, comparing
+// elements by JSON value (so 3 and 3.0 are counted once). If the underlying value is not an
+// array, 0 is returned.
+func (jp JPath) DistinctCount() int {
+	ary, ok := jp.I.([]interface{})
+
+	if !ok {
+		return 0
+	}
+
+	seen := jsonSet{}
+	count := 0
+
+	for _, elem := range ary {
+		if seen.add(elem) {
+			count += 1
+		}
+	}
+
+	return count
+}
+
+// Tally calls keyFn on each element of the underlying array and returns the number of elements
+// which produced each key. If the underlying value is not an array, an empty map is returned.
+func (jp JPath) Tally(keyFn func(v JPath) string) map[string]int {
+	ret := map[string]int{}
+
+	for i := 0 ; i < jp.Length() ; i += 1 {
+		ret[keyFn(jp.Index(i))] += 1
+	}
+
+	return ret
+}
+
+// Take returns a new JPath wrapping a copy of the first n elements of the underlying array. If
+// n exceeds the length of the array, the whole array is copied. If the underlying value is not
+// an array, a zero-value JPath is returned.
+func (jp JPath) Take(n int) JPath {
+	ary, ok := jp.I.([]interface{})
+
+	if !ok {
+		return JPath{}
+	}
+
+	n = clamp(n, len(ary))
+	ret := make([]interface{}, n)
+	copy(ret, ary[:n])
+
+	return JPath{I: ret}
+}
+
+// Drop returns a new JPath wrapping a copy of all but the first n elements of the underlying
+// array. If n exceeds the length of the array, the result is an empty array. If the underlying
+// value is not an array, a zero-value JPath is returned.
+func (jp JPath) Drop(n int) JPath {
+	ary, ok := jp.I.([]interface{})
+
+	if !ok {
+		return JPath{}
+	}
+
+	n = clamp(n, len(ary))
+	ret := make([]interface{}, len(ary) - n)
+	copy(ret, ary[n:])
+
+	return JPath{I: ret}
+}
+
+// clamp limits n to the range [0, limit].
+func clamp(n, limit int) int {
+	if n < 0 {
+		return 0
+	}
+
+	if n > limit {
+		return limit
+	}
+
+	return n
+}
+
+// IndicesWhere returns the positions of the elements of the underlying array for which fn
+// returns true, in order. If the underlying value is not an array, an empty slice is returned.
+func (jp JPath) IndicesWhere(fn func(v JPath) bool) []int {
+	ret := []int{}
+
+	for i := 0 ; i < jp.Length() ; i += 1 {
+		if fn(jp.Index(i)) {
+			ret = append(ret, i)
+		}
+	}
+
+	return ret
+}
+
+// FlattenOnce returns a new JPath wrapping the underlying array with one level of nesting
+// removed: elements which are arrays are replaced by their own elements, in order, and all
+// other elements are kept as-is. If the underlying value is not an array, a zero-value JPath is
+// returned.
+func (jp JPath) FlattenOnce() JPath {
+	ary, ok := jp.I.([]interface{})
+
+	if !ok {
+		return JPath{}
+	}
+
+	ret := []interface{}{}
+
+	for _, elem := range ary {
+		if sub, ok := elem.([]interface{}) ; ok {
+			ret = append(ret, sub...)
+		} else {
+			ret = append(ret, elem)
+		}
+	}
+
+	return JPath{I: ret}
+}
+
+// FlattenDeep returns a new JPath wrapping the underlying array with all nesting of arrays
+// removed, so the result contains no arrays at all. Objects are kept whole; arrays inside them
+// are not flattened. If the underlying value is not an array, a zero-value JPath is returned.
+func (jp JPath) FlattenDeep() JPath {
+	ary, ok := jp.I.([]interface{})
+
+	if !ok {
+		return JPath{}
+	}
+
+	return JPath{I: flattenDeep([]interface{}{}, ary)}
+}
+
+func flattenDeep(ret, ary []interface{}) []interface{} {
+	for _, elem := range ary {
+		if sub, ok := elem.([]interface{}) ; ok {
+			ret = flattenDeep(ret, sub)
+		} else {
+			ret = append(ret, elem)
+		}
+	}
+
+	return ret
+}
+
+// Union returns a new JPath wrapping an array of the elements of the underlying array followed
+// by those of each of others, keeping only the first occurrence of each value by JSON-value
+// equality. Any of the receiver or others which are not arrays are skipped.
+func (jp JPath) Union(others ...JPath) JPath {
+	ret := []interface{}{}
+	seen := jsonSet{}
+
+	for _, src := range append([]JPath{jp}, others...) {
+		ary, _ := src.I.([]interface{})
+
+		for _, elem := range ary {
+			if seen.add(elem) {
+				ret = append(ret, elem)
+			}
+		}
+	}
+
+	return JPath{I: ret}
+}
+
+// Intersect returns a new JPath wrapping an array of the elements of the underlying array which
+// also appear in other's array, by JSON-value equality. Elements keep the receiver's order, and
+// only the first occurrence of each value is kept. If either value is not an array, a
+// zero-value JPath is returned.
+func (jp JPath) Intersect(other JPath) JPath {
+	ary, ok := jp.I.([]interface{})
+	otherAry, otherOk := other.I.([]interface{})
+
+	if !ok || !otherOk {
+		return JPath{}
+	}
+
+	ret := []interface{}{}
+	others, seen := jsonSetOf(otherAry), jsonSet{}
+
+	for _, elem := range ary {
+		if others.contains(elem) && seen.add(elem) {
+			ret = append(ret, elem)
+		}
+	}
+
+	return JPath{I: ret}
+}
+
+// Difference returns a new JPath wrapping an array of the elements of the underlying array which
+// do not appear in other's array, by JSON-value equality, in the receiver's order. If other is
+// not an array, nothing is removed. If the underlying value is not an array, a zero-value JPath
+// is returned.
+func (jp JPath) Difference(other JPath) JPath {
+	ary, ok := jp.I.([]interface{})
+
+	if !ok {
+		return JPath{}
+	}
+
+	otherAry, _ := other.I.([]interface{})
+	others := jsonSetOf(otherAry)
+	ret := []interface{}{}
+
+	for _, elem := range ary {
+		if !others.contains(elem) {
+			ret = append(ret, elem)
+		}
+	}
+
+	return JPath{I: ret}
+}
+
+// CompactArray returns a new JPath wrapping the underlying array with its null elements removed,
+// preserving the order of the rest. Empty strings, arrays and objects are not null and are kept.
+// If the underlying value is not an array, a zero-value JPath is returned.
+func (jp JPath) CompactArray() JPath {
+	ary, ok := jp.I.([]interface{})
+
+	if !ok {
+		return JPath{}
+	}
+
+	ret := []interface{}{}
+
+	for _, elem := range ary {
+		if elem != nil {
+			ret = append(ret, elem)
+		}
+	}
+
+	return JPath{I: ret}
+}
+
+// KeyBy returns a new JPath wrapping an object which maps the named field of each element of the
+// underlying array, coerced with String, to a copy of that element. When several elements share
+// a key, the later one wins. Elements without the field (or with a null value for it) are
+// skipped. If the underlying value is not an array, a zero-value JPath is returned.
+func (jp JPath) KeyBy(field string) JPath {
+	ary, ok := jp.I.([]interface{})
+
+	if !ok {
+		return JPath{}
+	}
+
+	ret := map[string]interface{}{}
+
+	for i, elem := range ary {
+		key := jp.Index(i).Field(field)
+
+		if !key.IsNull() {
+			ret[key.String()] = deepCopy(elem)
+		}
+	}
+
+	return JPath{I: ret}
+}
+
+// PairsToMap builds a map from an underlying array of objects such as
+// [{"key": "a", "value": 1}, ...], mapping each element's keyField to its valueField, both
+// coerced with String. When several elements share a key, the later one wins. Elements without
+// the key field (or with a null value for it) are skipped. If the underlying value is not an
+// array, an empty map is returned.
+func (jp JPath) PairsToMap(keyField, valueField string) map[string]string {
+	ret := map[string]string{}
+
+	for i := 0 ; i < jp.Length() ; i += 1 {
+		elem := jp.Index(i)
+
+		if key := elem.Field(keyField) ; !key.IsNull() {
+			ret[key.String()] = elem.Field(valueField).String()
+		}
+	}
+
+	return ret
+}
+
+// FirstNonNull resolves path, as with Get, against each element of the underlying array in turn,
+// and returns the first result which is present and not null. This suits fallback lookups across
+// layers of configuration, such as FirstNonNull("timeout"). If no element qualifies, or the
+// underlying value is not an array, a zero-value JPath is returned.
+func (jp JPath) FirstNonNull(path string) JPath {
+	for i := 0 ; i < jp.Length() ; i += 1 {
+		if v := jp.Index(i).Get(path) ; !v.IsNull() {
+			return v
+		}
+	}
+
+	return JPath{}
+}
+
+// ZipMerge returns a new JPath wrapping an array which merges the underlying array with other's,
+// element by element. Where both arrays have an element at an index, objects are merged
+// recursively and any other values -- including arrays -- are taken from other. Where only one
+// array has an element, a copy of it is used, so the result is as long as the longer array. If
+// either value is not an array, a zero-value JPath is returned.
+func (jp JPath) ZipMerge(other JPath) JPath {
+	ary, ok := jp.I.([]interface{})
+	otherAry, otherOk := other.I.([]interface{})
+
+	if !ok || !otherOk {
+		return JPath{}
+	}
+
+	ret := make([]interface{}, len(ary))
+
+	for i, elem := range ary {
+		if i < len(otherAry) {
+			ret[i] = mergeValues(elem, otherAry[i])
+		} else {
+			ret[i] = deepCopy(elem)
+		}
+	}
+
+	for i := len(ary) ; i < len(otherAry) ; i += 1 {
+		ret = append(ret, deepCopy(otherAry[i]))
+	}
+
+	return JPath{I: ret}
+}
+
+// mergeValues returns a deep copy of a with b merged over it: objects are merged recursively, and
+// anything else is replaced by b.
+func mergeValues(a, b interface{}) interface{} {
+	obj, ok := a.(map[string]interface{})
+	otherObj, otherOk := b.(map[string]interface{})
+
+	if !ok || !otherOk {
+		return deepCopy(b)
+	}
+
+	ret := deepCopy(obj).(map[string]interface{})
+
+	for k, v := range otherObj {
+		if cur, present := obj[k] ; present {
+			ret[k] = mergeValues(cur, v)
+		} else {
+			ret[k] = deepCopy(v)
+		}
+	}
+
+	return ret
+}
+
+// Unwrap returns a JPath wrapping the only element of the underlying array, if it is an array
+// of exactly one element. Otherwise, the receiver is returned unchanged. This smooths over APIs
+// which sometimes wrap a single value in an array.
+func (jp JPath) Unwrap() JPath {
+	if ary, ok := jp.I.([]interface{}) ; ok && len(ary) == 1 {
+		return jp.Index(0)
+	}
+
+	return jp
+}
+
+// ElementType returns the JSON type name ("null", "boolean", "number", "string", "array" or
+// "object") shared by every element of the underlying array, or "mixed" if the elements are of
+// more than one type. Empty arrays, and values which aren't arrays, yield "".
+func (jp JPath) ElementType() string {
+	ary, _ := jp.I.([]interface{})
+	ret := ""
+
+	for _, elem := range ary {
+		if kind := jsonKind(elem) ; ret == "" {
+			ret = kind
+		} else if kind != ret {
+			return "mixed"
+		}
+	}
+
+	return ret
+}
+
+// IsHomogeneous reports whether every element of the underlying array is of the same JSON type,
+// as determined by ElementType. Empty arrays, and values which aren't arrays, are considered
+// homogeneous.
+func (jp JPath) IsHomogeneous() bool {
+	return jp.ElementType() != "mixed"
+}
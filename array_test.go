@@ -0,0 +1,519 @@
+package jpath
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestChunk(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`[1, 2, 3, 4, 5, 6, 7]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	chunks := jp.Chunk(3)
+	expectedSizes := []int{3, 3, 1}
+
+	if len(chunks) != len(expectedSizes) {
+		t.Fatalf("Expected %d chunks, got %d", len(expectedSizes), len(chunks))
+	}
+
+	for i, size := range expectedSizes {
+		if chunks[i].Length() != size {
+			t.Errorf("Expected chunk %d to have %d elements, got %d", i, size, chunks[i].Length())
+		}
+	}
+
+	if last := chunks[2].Index(0).Int() ; last != 7 {
+		t.Errorf("Expected final chunk to hold 7, got %d", last)
+	}
+
+	if len(jp.Chunk(0)) != 0 {
+		t.Errorf("Expected no chunks for size 0")
+	}
+
+	if len(jp.Index(0).Chunk(3)) != 0 {
+		t.Errorf("Expected no chunks for a non-array")
+	}
+}
+
+func TestDistinctCount(t *testing.T) {
+	jp := JPath{I: []interface{}{float64(1), 3, float64(3), float64(2), 1}}
+
+	if count := jp.DistinctCount() ; count != 3 {
+		t.Errorf("Expected 3 distinct numbers, got %d", count)
+	}
+
+	if er := jp.ParseString(`["a", "b", "a", "c", "b"]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if count := jp.DistinctCount() ; count != 3 {
+		t.Errorf("Expected 3 distinct strings, got %d", count)
+	}
+
+	if count := jp.Index(0).DistinctCount() ; count != 0 {
+		t.Errorf("Expected 0 for a non-array, got %d", count)
+	}
+
+	if er := jp.ParseString(`[{"a": 1, "b": [2]}, {"b": [2.0], "a": 1}, {"a": 1}, null, null, [], {}]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if count := jp.DistinctCount() ; count != 5 {
+		t.Errorf("Expected 5 distinct values, got %d", count)
+	}
+
+	if er := jp.ParseString(`[0, -0, 0.0, [-0], [0]]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if count := jp.DistinctCount() ; count != 2 {
+		t.Errorf("Expected -0 and 0 to count once, got %d", count)
+	}
+}
+
+func BenchmarkDistinctCount(b *testing.B) {
+	ary := make([]interface{}, 10000)
+
+	for i := range ary {
+		ary[i] = map[string]interface{}{"id": float64(i % 5000), "tags": []interface{}{"a", "b"}}
+	}
+
+	jp := JPath{I: ary}
+	b.ResetTimer()
+
+	for i := 0 ; i < b.N ; i += 1 {
+		if count := jp.DistinctCount() ; count != 5000 {
+			b.Fatalf("Expected 5000 distinct values, got %d", count)
+		}
+	}
+}
+
+func TestTally(t *testing.T) {
+	var jp JPath
+	jsonBlob := `[
+		{"id": 1, "status": "active"},
+		{"id": 2, "status": "banned"},
+		{"id": 3, "status": "active"},
+		{"id": 4}
+	]`
+
+	if er := jp.ParseString(jsonBlob) ; er != nil {
+		t.Fatal(er)
+	}
+
+	tally := jp.Tally(func(v JPath) string {
+		return v.Field("status").String()
+	})
+
+	expected := map[string]int{"active": 2, "banned": 1, "": 1}
+
+	if len(tally) != len(expected) {
+		t.Errorf("Expected %v, got %v", expected, tally)
+	}
+
+	for k, count := range expected {
+		if tally[k] != count {
+			t.Errorf("Expected %d for %q, got %d", count, k, tally[k])
+		}
+	}
+
+	if len(jp.Index(0).Tally(func(v JPath) string { return "" })) != 0 {
+		t.Errorf("Expected an empty tally for a non-array")
+	}
+}
+
+func TestTakeDrop(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`[1, 2, 3, 4]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	take := jp.Take(2)
+	if take.Length() != 2 || take.Index(0).Int() != 1 || take.Index(1).Int() != 2 {
+		t.Errorf("Expected [1, 2], got %#v", take.I)
+	}
+
+	drop := jp.Drop(2)
+	if drop.Length() != 2 || drop.Index(0).Int() != 3 || drop.Index(1).Int() != 4 {
+		t.Errorf("Expected [3, 4], got %#v", drop.I)
+	}
+
+	if take := jp.Take(10) ; take.Length() != 4 {
+		t.Errorf("Expected Take(10) to return all 4 elements, got %#v", take.I)
+	}
+
+	if drop := jp.Drop(10) ; drop.IsNull() || drop.Length() != 0 {
+		t.Errorf("Expected Drop(10) to return an empty array, got %#v", drop.I)
+	}
+
+	if !jp.Index(0).Take(1).IsNull() || !jp.Index(0).Drop(1).IsNull() {
+		t.Errorf("Expected zero-values for a non-array")
+	}
+}
+
+func TestIndicesWhere(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`[{"done": 1}, {"done": 0}, {"done": 1}, {}]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	isDone := func(v JPath) bool {
+		return v.Field("done").Int() == 1
+	}
+
+	indices := jp.IndicesWhere(isDone)
+
+	if len(indices) != 2 || indices[0] != 0 || indices[1] != 2 {
+		t.Errorf("Expected [0 2], got %v", indices)
+	}
+
+	if indices := jp.Index(0).IndicesWhere(isDone) ; len(indices) != 0 {
+		t.Errorf("Expected no indices for a non-array, got %v", indices)
+	}
+}
+
+// intsOf returns the Int of each element of jp.
+func intsOf(jp JPath) []int {
+	ret := []int{}
+
+	for i := 0 ; i < jp.Length() ; i += 1 {
+		ret = append(ret, jp.Index(i).Int())
+	}
+
+	return ret
+}
+
+func TestFlattenOnce(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`[[1, 2], [3], [4, 5]]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if flat := intsOf(jp.FlattenOnce()) ; fmt.Sprint(flat) != "[1 2 3 4 5]" {
+		t.Errorf("Expected [1 2 3 4 5], got %v", flat)
+	}
+
+	if er := jp.ParseString(`[1, [2, [3]], "x", []]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	flat := jp.FlattenOnce()
+
+	if flat.Length() != 4 || flat.Index(1).Int() != 2 || flat.Index(2).Length() != 1 || flat.Index(3).String() != "x" {
+		t.Errorf("Expected [1, 2, [3], \"x\"], got %#v", flat.I)
+	}
+
+	if !jp.Index(0).FlattenOnce().IsNull() {
+		t.Errorf("Expected a zero-value for a non-array")
+	}
+}
+
+func TestFlattenDeep(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`[1, [2, [3, 4]], 5]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if flat := intsOf(jp.FlattenDeep()) ; fmt.Sprint(flat) != "[1 2 3 4 5]" {
+		t.Errorf("Expected [1 2 3 4 5], got %v", flat)
+	}
+
+	if er := jp.ParseString(`[[{"ids": [1, 2]}], [[3]]]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	flat := jp.FlattenDeep()
+
+	if flat.Length() != 2 || flat.Index(0).Field("ids").Length() != 2 || flat.Index(1).Int() != 3 {
+		t.Errorf(`Expected [{"ids": [1, 2]}, 3], got %#v`, flat.I)
+	}
+
+	if !jp.Index(5).FlattenDeep().IsNull() {
+		t.Errorf("Expected a zero-value for a non-array")
+	}
+}
+
+// stringsOf returns the String of each element of jp.
+func stringsOf(jp JPath) []string {
+	ret := []string{}
+
+	for i := 0 ; i < jp.Length() ; i += 1 {
+		ret = append(ret, jp.Index(i).String())
+	}
+
+	return ret
+}
+
+func TestUnion(t *testing.T) {
+	var a, b JPath
+
+	if er := a.ParseString(`["go", "json", "go"]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if er := b.ParseString(`["yaml", "json", "toml"]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	union := a.Union(b, JPath{I: "skipped"})
+
+	if tags := stringsOf(union) ; fmt.Sprint(tags) != "[go json yaml toml]" {
+		t.Errorf("Expected [go json yaml toml], got %v", tags)
+	}
+
+	if a.Length() != 3 {
+		t.Errorf("Original value was modified")
+	}
+
+	if er := a.ParseString(`[0]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if er := b.ParseString(`[-0]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if length := a.Union(b).Length() ; length != 1 {
+		t.Errorf("Expected -0 and 0 to be merged, got %d elements", length)
+	}
+}
+
+func TestIntersect(t *testing.T) {
+	var a, b, c JPath
+
+	if er := a.ParseString(`[5, 1, 4, 2, 3]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if er := b.ParseString(`[2, 3.0, 5, 9]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if er := c.ParseString(`[7, 8]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if both := intsOf(a.Intersect(b)) ; fmt.Sprint(both) != "[5 2 3]" {
+		t.Errorf("Expected [5 2 3], got %v", both)
+	}
+
+	if none := a.Intersect(c) ; none.IsNull() || none.Length() != 0 {
+		t.Errorf("Expected an empty array, got %#v", none.I)
+	}
+
+	if !a.Intersect(JPath{I: "x"}).IsNull() {
+		t.Errorf("Expected a zero-value for a non-array")
+	}
+}
+
+func TestDifference(t *testing.T) {
+	var before, after, unrelated JPath
+
+	if er := before.ParseString(`["a", "b", "c", "d"]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if er := after.ParseString(`["b", "d", "e"]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if er := unrelated.ParseString(`["x", "y"]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if removed := stringsOf(before.Difference(after)) ; fmt.Sprint(removed) != "[a c]" {
+		t.Errorf("Expected [a c], got %v", removed)
+	}
+
+	if removed := stringsOf(before.Difference(unrelated)) ; fmt.Sprint(removed) != "[a b c d]" {
+		t.Errorf("Expected [a b c d], got %v", removed)
+	}
+
+	if !before.Index(0).Difference(after).IsNull() {
+		t.Errorf("Expected a zero-value for a non-array")
+	}
+}
+
+func TestCompactArray(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`[1, null, 2, null, 3]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if compact := intsOf(jp.CompactArray()) ; fmt.Sprint(compact) != "[1 2 3]" {
+		t.Errorf("Expected [1 2 3], got %v", compact)
+	}
+
+	if er := jp.ParseString(`["", null, [], {}]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if compact := jp.CompactArray() ; compact.Length() != 3 {
+		t.Errorf("Expected empty values to be kept, got %#v", compact.I)
+	}
+
+	if !jp.Index(0).CompactArray().IsNull() {
+		t.Errorf("Expected a zero-value for a non-array")
+	}
+}
+
+func TestKeyBy(t *testing.T) {
+	var jp JPath
+	jsonBlob := `[
+		{"id": "a", "v": 1},
+		{"id": "b", "v": 2},
+		{"v": 3},
+		{"id": "a", "v": 4}
+	]`
+
+	if er := jp.ParseString(jsonBlob) ; er != nil {
+		t.Fatal(er)
+	}
+
+	byID := jp.KeyBy("id")
+
+	if len(byID.Fields()) != 2 {
+		t.Errorf("Expected 2 keys, got %v", byID.Fields())
+	}
+
+	if v := byID.Get("a.v").Int() ; v != 4 {
+		t.Errorf("Expected the later duplicate to win, got %d", v)
+	}
+
+	if v := byID.Get("b.v").Int() ; v != 2 {
+		t.Errorf("Expected 2, got %d", v)
+	}
+
+	if !jp.Index(0).KeyBy("id").IsNull() {
+		t.Errorf("Expected a zero-value for a non-array")
+	}
+}
+
+func TestPairsToMap(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`[{"name": "region", "val": "eu"}, {"name": "tier", "val": "gold"}, {"val": "orphan"}, {"name": "region", "val": "us"}, "junk"]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	expected := map[string]string{"region": "us", "tier": "gold"}
+
+	if actual := jp.PairsToMap("name", "val") ; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v, got %v", expected, actual)
+	}
+
+	if actual := jp.Index(0).PairsToMap("name", "val") ; actual == nil || len(actual) != 0 {
+		t.Errorf("Expected an empty map for a non-array, got %v", actual)
+	}
+}
+
+func TestFirstNonNull(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`[{"name": "env"}, {"db": {"timeout": null}}, {"db": {"timeout": 30}}, {"db": {"timeout": 60}}]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if timeout := jp.FirstNonNull("db.timeout").Int() ; timeout != 30 {
+		t.Errorf("Expected 30 from the first layer defining it, got %d", timeout)
+	}
+
+	if name := jp.FirstNonNull("name").String() ; name != "env" {
+		t.Errorf("Expected env, got %q", name)
+	}
+
+	if !jp.FirstNonNull("db.missing").IsNull() {
+		t.Errorf("Expected a zero-value when no element has the path")
+	}
+
+	if !jp.Index(2).FirstNonNull("db").IsNull() {
+		t.Errorf("Expected a zero-value for a non-array")
+	}
+}
+
+func TestZipMerge(t *testing.T) {
+	var base, overlay JPath
+
+	if er := base.ParseString(`[{"id": 1, "name": "a", "meta": {"x": 1, "y": 2}}, {"id": 2, "name": "b"}, {"id": 3}]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if er := overlay.ParseString(`[{"name": "A", "meta": {"y": 20, "z": 30}}, "scalar"]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	expected := []interface{}{
+		map[string]interface{}{"id": 1.0, "name": "A", "meta": map[string]interface{}{"x": 1.0, "y": 20.0, "z": 30.0}},
+		"scalar",
+		map[string]interface{}{"id": 3.0},
+	}
+
+	if merged := base.ZipMerge(overlay) ; !reflect.DeepEqual(merged.I, expected) {
+		t.Errorf("Expected %#v, got %#v", expected, merged.I)
+	}
+
+	if merged := overlay.ZipMerge(base) ; merged.Length() != 3 || merged.Get("0.name").String() != "a" || merged.Get("2.id").Int() != 3 {
+		t.Errorf("Expected the longer argument to fill in the tail, got %#v", merged.I)
+	}
+
+	if merged := base.ZipMerge(base) ; !reflect.DeepEqual(merged.I, base.I) {
+		t.Errorf("Expected merging an array with itself to reproduce it, got %#v", merged.I)
+	}
+
+	if base.Get("0.meta.z").I != nil {
+		t.Errorf("Original value was modified")
+	}
+
+	if !base.ZipMerge(overlay.Index(0)).IsNull() {
+		t.Errorf("Expected a zero-value for a non-array")
+	}
+}
+
+func TestUnwrap(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`{"one": [{"id": 1}], "many": [1, 2], "plain": {"id": 2}}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if id := jp.Field("one").Unwrap().Field("id").Int() ; id != 1 {
+		t.Errorf("Expected the single element to be unwrapped, got %d", id)
+	}
+
+	if many := jp.Field("many").Unwrap() ; many.Length() != 2 {
+		t.Errorf("Expected a multi-element array to be left alone, got %#v", many.I)
+	}
+
+	if id := jp.Field("plain").Unwrap().Field("id").Int() ; id != 2 {
+		t.Errorf("Expected a plain object to be left alone, got %d", id)
+	}
+}
+
+func TestElementType(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`{"numbers": [1, 2.5, -3], "objects": [{}, {"a": 1}], "mixed": [1, "2", null], "empty": [], "scalar": 4}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	expected := map[string]string{"numbers": "number", "objects": "object", "mixed": "mixed", "empty": "", "scalar": ""}
+
+	for field, kind := range expected {
+		if actual := jp.Field(field).ElementType() ; actual != kind {
+			t.Errorf("Expected %s to have element type %q, got %q", field, kind, actual)
+		}
+
+		if actual := jp.Field(field).IsHomogeneous() ; actual != (kind != "mixed") {
+			t.Errorf("Expected IsHomogeneous for %s to be %v, got %v", field, kind != "mixed", actual)
+		}
+	}
+}
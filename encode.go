@@ -0,0 +1,198 @@
+package jpath
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+	"strings"
+)
+
+// finiteFloats returns v with any NaN or infinite floats, which JSON can't represent, replaced
+// by nil. Objects and arrays are only copied where a replacement was needed, so v is returned
+// as-is when it holds no such floats.
+func finiteFloats(v interface{}) interface{} {
+	ret, _ := replaceNonFinite(v)
+	return ret
+}
+
+func replaceNonFinite(v interface{}) (interface{}, bool) {
+	switch val := v.(type) {
+	case float64:
+		if math.IsNaN(val) || math.IsInf(val, 0) {
+			return nil, true
+		}
+
+	case float32:
+		if math.IsNaN(float64(val)) || math.IsInf(float64(val), 0) {
+			return nil, true
+		}
+
+	case map[string]interface{}:
+		var ret map[string]interface{}
+
+		for k, child := range val {
+			if replaced, changed := replaceNonFinite(child) ; changed {
+				if ret == nil {
+					ret = make(map[string]interface{}, len(val))
+
+					for k, child := range val {
+						ret[k] = child
+					}
+				}
+
+				ret[k] = replaced
+			}
+		}
+
+		if ret != nil {
+			return ret, true
+		}
+
+	case []interface{}:
+		var ret []interface{}
+
+		for i, child := range val {
+			if replaced, changed := replaceNonFinite(child) ; changed {
+				if ret == nil {
+					ret = make([]interface{}, len(val))
+					copy(ret, val)
+				}
+
+				ret[i] = replaced
+			}
+		}
+
+		if ret != nil {
+			return ret, true
+		}
+	}
+
+	return v, false
+}
+
+// MarshalJSON implements json.Marshaler, encoding the underlying value rather than the JPath
+// itself. NaN and infinite floats, which encoding/json refuses to marshal, are written as null.
+func (jp JPath) MarshalJSON() ([]byte, error) {
+	return json.Marshal(finiteFloats(jp.I))
+}
+
+// UnmarshalJSON implements json.Unmarshaler, parsing b with ParseBytes so that a JPath nested in
+// a struct or map round-trips through MarshalJSON.
+func (jp *JPath) UnmarshalJSON(b []byte) error {
+	return jp.ParseBytes(b)
+}
+
+// Snapshot renders the underlying value as indented JSON with the keys of every object sorted,
+// so equal documents always render identically. This makes it suitable for comparing against
+// golden files. If the value can't be marshaled, an empty string is returned.
+func (jp JPath) Snapshot() string {
+	// encoding/json always writes map keys in sorted order.
+	bytes, er := json.MarshalIndent(finiteFloats(jp.I), "", "  ")
+	if er != nil {
+		return ""
+	}
+
+	return string(bytes)
+}
+
+// countingWriter discards everything written to it, counting the bytes.
+type countingWriter struct {
+	n int
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	cw.n += len(p)
+	return len(p), nil
+}
+
+// ByteSize returns the length of the compact JSON encoding of the underlying value, as returned
+// by MarshalJSON, without holding the encoding in memory. A zero-value JPath has a size of 4 (the
+// length of "null"). If the value can't be marshaled, -1 is returned.
+func (jp JPath) ByteSize() int {
+	cw := countingWriter{}
+
+	if er := json.NewEncoder(&cw).Encode(finiteFloats(jp.I)) ; er != nil {
+		return -1
+	}
+
+	// Encode terminates each value with a newline, which MarshalJSON doesn't.
+	return cw.n - 1
+}
+
+// ToEnv renders the underlying value as lines of KEY=value, as found in .env files, one for each
+// leaf given by Flatten, sorted by key. Keys are built by upper-casing the leaf's path segments,
+// replacing any character other than a letter, digit or underscore with '_', and joining the
+// segments with '_', so array indices become numeric suffixes:
+//
+//     {"database": {"host": "localhost", "ports": [5432]}}
+//
+//     DATABASE_HOST=localhost
+//     DATABASE_PORTS_0=5432
+//
+// Strings are written as-is, nulls as empty values, and other values as compact JSON. Values
+// containing whitespace or characters special to the shell are wrapped in single quotes. When
+// several paths map to the same key, as "a.b" and "a_b" do, the path which sorts last wins. A
+// scalar receiver, which has no path, yields "".
+func (jp JPath) ToEnv() string {
+	flat := jp.Flatten()
+	values := map[string]string{}
+
+	for _, path := range flat.SortedFields() {
+		segments := splitPath(path)
+		if len(segments) == 0 {
+			continue
+		}
+
+		for i, seg := range segments {
+			segments[i] = envKey(seg)
+		}
+
+		values[strings.Join(segments, "_")] = envValue(flat.Field(path))
+	}
+
+	keys := make([]string, 0, len(values))
+
+	for k := range values {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+	ret := strings.Builder{}
+
+	for _, k := range keys {
+		ret.WriteString(k + "=" + values[k] + "\n")
+	}
+
+	return ret.String()
+}
+
+// envKey upper-cases seg and replaces characters which aren't valid in an environment
+// variable name with '_'.
+func envKey(seg string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_':
+			return r
+		}
+
+		return '_'
+	}, seg)
+}
+
+// envValue renders v as the value half of a .env line.
+func envValue(v JPath) string {
+	str, ok := v.I.(string)
+
+	if !ok && v.I != nil {
+		bytes, _ := v.MarshalJSON()
+		str = string(bytes)
+	}
+
+	if strings.ContainsAny(str, " \t\r\n'\"\\$`#;&|<>()*?[]{}~!") {
+		return "'" + strings.Replace(str, "'", `'\''`, -1) + "'"
+	}
+
+	return str
+}
@@ -0,0 +1,173 @@
+package jpath
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+	var a, b JPath
+
+	if er := a.ParseString(`{"b": {"y": 2, "x": 1}, "a": [3, {"d": 4, "c": 5}]}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if er := b.ParseString(`{"a": [3, {"c": 5, "d": 4}], "b": {"x": 1, "y": 2}}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	expected := `{
+  "a": [
+    3,
+    {
+      "c": 5,
+      "d": 4
+    }
+  ],
+  "b": {
+    "x": 1,
+    "y": 2
+  }
+}`
+
+	if snapshot := a.Snapshot() ; snapshot != expected {
+		t.Errorf("Unexpected snapshot:\n%s", snapshot)
+	}
+
+	if a.Snapshot() != b.Snapshot() {
+		t.Errorf("Expected reordered documents to have identical snapshots")
+	}
+}
+
+func TestByteSize(t *testing.T) {
+	documents := []string{
+		`null`,
+		`"café <b>"`,
+		`[1, 2.5, true, null]`,
+		`{"name": "widget", "tags": ["a", "b"], "nested": {"deep": [{}]}}`,
+	}
+
+	for _, doc := range documents {
+		var jp JPath
+
+		if er := jp.ParseString(doc) ; er != nil {
+			t.Fatal(er)
+		}
+
+		bytes, er := jp.MarshalJSON()
+		if er != nil {
+			t.Fatal(er)
+		}
+
+		if size := jp.ByteSize() ; size != len(bytes) {
+			t.Errorf("Expected a size of %d for %s, got %d", len(bytes), doc, size)
+		}
+	}
+
+	if size := (JPath{}).ByteSize() ; size != 4 {
+		t.Errorf("Expected a size of 4 for a zero-value, got %d", size)
+	}
+}
+
+func TestMarshalNonFinite(t *testing.T) {
+	jp := JPath{I: map[string]interface{}{
+		"nan":    math.NaN(),
+		"inf":    math.Inf(1),
+		"list":   []interface{}{1.5, math.Inf(-1)},
+		"normal": 2.0,
+	}}
+
+	bytes, er := jp.MarshalJSON()
+	if er != nil {
+		t.Fatal(er)
+	}
+
+	expected := `{"inf":null,"list":[1.5,null],"nan":null,"normal":2}`
+
+	if string(bytes) != expected {
+		t.Errorf("Expected %s, got %s", expected, bytes)
+	}
+
+	wrapped, er := json.Marshal(map[string]JPath{"doc": jp})
+	if er != nil {
+		t.Fatal(er)
+	}
+
+	if string(wrapped) != `{"doc":` + expected + `}` {
+		t.Errorf("Expected MarshalJSON to be used when nested, got %s", wrapped)
+	}
+
+	if !math.IsNaN(jp.Field("nan").Float64()) {
+		t.Errorf("Original value was modified")
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	type envelope struct {
+		ID  int   `json:"id"`
+		Doc JPath `json:"doc"`
+	}
+
+	var jp JPath
+
+	if er := jp.ParseString(`{"name": "widget", "tags": ["a", "b"], "size": {"w": 2, "h": null}}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	bytes, er := json.Marshal(envelope{7, jp})
+	if er != nil {
+		t.Fatal(er)
+	}
+
+	var decoded envelope
+
+	if er := json.Unmarshal(bytes, &decoded) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if decoded.ID != 7 || !reflect.DeepEqual(decoded.Doc.I, jp.I) {
+		t.Errorf("Expected the document to survive a round-trip, got %#v", decoded.Doc.I)
+	}
+
+	if er := json.Unmarshal([]byte(`{"id": 1, "doc": null}`), &decoded) ; er != nil || decoded.Doc.I != nil {
+		t.Errorf("Expected null to unmarshal to a zero-value, got %#v (%v)", decoded.Doc.I, er)
+	}
+}
+
+func TestToEnv(t *testing.T) {
+	var jp JPath
+
+	jsonBlob := `{
+		"database": {"host": "localhost", "port": 5432, "password": "it's secret"},
+		"feature-flags": {"beta": true},
+		"hosts": ["a.example.com", "b.example.com"],
+		"greeting": "hello world",
+		"retries": null
+	}`
+
+	if er := jp.ParseString(jsonBlob) ; er != nil {
+		t.Fatal(er)
+	}
+
+	expected := strings.Join([]string{
+		"DATABASE_HOST=localhost",
+		`DATABASE_PASSWORD='it'\''s secret'`,
+		"DATABASE_PORT=5432",
+		"FEATURE_FLAGS_BETA=true",
+		"GREETING='hello world'",
+		"HOSTS_0=a.example.com",
+		"HOSTS_1=b.example.com",
+		"RETRIES=",
+	}, "\n") + "\n"
+
+	if env := jp.ToEnv() ; env != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, env)
+	}
+
+	if env := jp.Field("greeting").ToEnv() ; env != "" {
+		t.Errorf("Expected nothing for a scalar, got %q", env)
+	}
+}
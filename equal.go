@@ -0,0 +1,84 @@
+package jpath
+
+import (
+	"encoding/json"
+)
+
+// jsonNumber returns v as a float64 if it is one of the numeric types that may appear in an
+// underlying value. Strings are not coerced.
+func jsonNumber(v interface{}) (float64, bool) {
+	switch num := v.(type) {
+	case float64:
+		return num, true
+	case float32:
+		return float64(num), true
+	case int:
+		return float64(num), true
+	case int32:
+		return float64(num), true
+	case int64:
+		return float64(num), true
+	case uint32:
+		return float64(num), true
+	case uint64:
+		return float64(num), true
+	case json.Number:
+		f, er := num.Float64()
+		return f, er == nil
+	}
+
+	return 0, false
+}
+
+// jsonEqual reports whether a and b represent the same JSON value. Numbers are compared by
+// value regardless of their Go type, so 3 and 3.0 are equal.
+func jsonEqual(a, b interface{}) bool {
+	if an, ok := jsonNumber(a) ; ok {
+		bn, ok := jsonNumber(b)
+		return ok && an == bn
+	}
+
+	switch av := a.(type) {
+	case nil:
+		return b == nil
+
+	case bool:
+		bv, ok := b.(bool)
+		return ok && av == bv
+
+	case string:
+		bv, ok := b.(string)
+		return ok && av == bv
+
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+
+		for i := range av {
+			if !jsonEqual(av[i], bv[i]) {
+				return false
+			}
+		}
+
+		return true
+
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+
+		for k, achild := range av {
+			bchild, ok := bv[k]
+			if !ok || !jsonEqual(achild, bchild) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
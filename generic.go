@@ -0,0 +1,62 @@
+package jpath
+
+import (
+	"encoding/json"
+)
+
+// decodeAs converts v into a T by round-tripping it through encoding/json.
+func decodeAs[T any](v interface{}) (T, error) {
+	var ret T
+
+	bytes, er := json.Marshal(v)
+	if er != nil {
+		return ret, er
+	}
+
+	er = json.Unmarshal(bytes, &ret)
+	return ret, er
+}
+
+// MapAs decodes each field of the underlying object into a T, as encoding/json would. Fields
+// which fail to decode are skipped. If the underlying value is not an object, an empty map is
+// returned.
+//
+//     scores := jpath.MapAs[int](jp.Field("scores"))
+func MapAs[T any](jp JPath) map[string]T {
+	ret := map[string]T{}
+	obj, ok := jp.I.(map[string]interface{})
+
+	if !ok {
+		return ret
+	}
+
+	for k, v := range obj {
+		if val, er := decodeAs[T](v) ; er == nil {
+			ret[k] = val
+		}
+	}
+
+	return ret
+}
+
+// SliceAs decodes each element of the underlying array into a T, as encoding/json would.
+// Elements which fail to decode are skipped. If the underlying value is not an array, an empty
+// slice is returned.
+//
+//     users := jpath.SliceAs[User](jp.Field("users"))
+func SliceAs[T any](jp JPath) []T {
+	ret := []T{}
+	ary, ok := jp.I.([]interface{})
+
+	if !ok {
+		return ret
+	}
+
+	for _, v := range ary {
+		if val, er := decodeAs[T](v) ; er == nil {
+			ret = append(ret, val)
+		}
+	}
+
+	return ret
+}
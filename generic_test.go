@@ -0,0 +1,71 @@
+package jpath
+
+import "testing"
+
+func TestMapAs(t *testing.T) {
+	var jp JPath
+	jsonBlob := `{
+		"scores": {"alice": 10, "bob": 7, "carol": "n/a"},
+		"users": {
+			"a": {"name": "alice", "age": 30},
+			"b": {"name": "bob", "age": 41}
+		}
+	}`
+
+	if er := jp.ParseString(jsonBlob) ; er != nil {
+		t.Fatal(er)
+	}
+
+	scores := MapAs[int](jp.Field("scores"))
+
+	if len(scores) != 2 || scores["alice"] != 10 || scores["bob"] != 7 {
+		t.Errorf("Expected alice and bob's scores, got %v", scores)
+	}
+
+	type user struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+
+	users := MapAs[user](jp.Field("users"))
+
+	if users["a"] != (user{"alice", 30}) || users["b"] != (user{"bob", 41}) {
+		t.Errorf("Unexpected users %v", users)
+	}
+
+	if len(MapAs[int](jp.Field("missing"))) != 0 {
+		t.Errorf("Expected an empty map for a non-object")
+	}
+}
+
+func TestSliceAs(t *testing.T) {
+	var jp JPath
+	jsonBlob := `{
+		"ids": [1, 2, "three", 4],
+		"users": [{"name": "alice"}, "bob", {"name": "carol"}]
+	}`
+
+	if er := jp.ParseString(jsonBlob) ; er != nil {
+		t.Fatal(er)
+	}
+
+	ids := SliceAs[int](jp.Field("ids"))
+
+	if len(ids) != 3 || ids[0] != 1 || ids[1] != 2 || ids[2] != 4 {
+		t.Errorf("Expected [1 2 4], got %v", ids)
+	}
+
+	type user struct {
+		Name string `json:"name"`
+	}
+
+	users := SliceAs[user](jp.Field("users"))
+
+	if len(users) != 2 || users[0].Name != "alice" || users[1].Name != "carol" {
+		t.Errorf("Expected alice and carol, got %v", users)
+	}
+
+	if len(SliceAs[int](jp.Field("missing"))) != 0 {
+		t.Errorf("Expected an empty slice for a non-array")
+	}
+}
@@ -0,0 +1,93 @@
+package jpath
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// MaxRequestBodySize is the largest request body, in bytes, that FromRequest will read.
+var MaxRequestBodySize int64 = 10 << 20
+
+// BodyTooLargeError is returned by FromRequest when the request body exceeds MaxRequestBodySize.
+type BodyTooLargeError struct {
+	Limit int64
+}
+
+func (e *BodyTooLargeError) Error() string {
+	return fmt.Sprintf("jpath: request body exceeds %d bytes", e.Limit)
+}
+
+// contextReader fails reads once its context is done.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (cr contextReader) Read(p []byte) (int, error) {
+	if er := cr.ctx.Err() ; er != nil {
+		return 0, er
+	}
+
+	return cr.r.Read(p)
+}
+
+// FromRequest reads the body of r and parses it as JSON, closing the body afterward. Reading
+// stops early if the request's context is cancelled. If the body is larger than
+// MaxRequestBodySize, a *BodyTooLargeError is returned.
+func FromRequest(r *http.Request) (JPath, error) {
+	var jp JPath
+
+	if r.Body == nil {
+		er := jp.ParseBytes(nil)
+		return jp, er
+	}
+
+	defer r.Body.Close()
+
+	limit := MaxRequestBodySize
+	body := contextReader{r.Context(), io.LimitReader(r.Body, limit + 1)}
+
+	bytes, er := ioutil.ReadAll(body)
+	if er != nil {
+		return jp, er
+	}
+
+	if int64(len(bytes)) > limit {
+		return jp, &BodyTooLargeError{limit}
+	}
+
+	er = jp.ParseBytes(bytes)
+	return jp, er
+}
+
+// WriteJSON sets the Content-Type of w to application/json, writes the status code, then encodes
+// the underlying value as the response body, as MarshalJSON would. A zero-value JPath writes
+// null.
+func (jp JPath) WriteJSON(w http.ResponseWriter, status int) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	return json.NewEncoder(w).Encode(finiteFloats(jp.I))
+}
+
+// ETag returns a weak HTTP entity tag for the underlying value, W/ followed by 32 hex digits in
+// double quotes, suitable for the ETag response header and for comparing against If-None-Match.
+// It is derived from a SHA-256 hash of the value's canonical encoding -- compact JSON with sorted
+// keys and numbers normalized as Normalize does -- so documents which differ only in key order or
+// number representation share an ETag. The tag is weak because such documents are semantically
+// equal rather than byte-for-byte identical. If the value can't be marshaled, "" is returned.
+func (jp JPath) ETag() string {
+	bytes, er := jp.Normalize().MarshalJSON()
+	if er != nil {
+		return ""
+	}
+
+	sum := sha256.Sum256(bytes)
+	return `W/"` + hex.EncodeToString(sum[:16]) + `"`
+}
@@ -0,0 +1,130 @@
+package jpath
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFromRequest(t *testing.T) {
+	req := httptest.NewRequest("POST", "/", strings.NewReader(`{"status": "ok", "count": 3}`))
+
+	jp, er := FromRequest(req)
+	if er != nil {
+		t.Fatal(er)
+	}
+
+	if status := jp.Field("status").String() ; status != "ok" {
+		t.Errorf("Expected ok, got %s", status)
+	}
+
+	if count := jp.Field("count").Int() ; count != 3 {
+		t.Errorf("Expected 3, got %d", count)
+	}
+}
+
+func TestFromRequestTooLarge(t *testing.T) {
+	defer func(limit int64) { MaxRequestBodySize = limit }(MaxRequestBodySize)
+	MaxRequestBodySize = 16
+
+	req := httptest.NewRequest("POST", "/", strings.NewReader(`{"status": "this body is far too long"}`))
+
+	_, er := FromRequest(req)
+	if _, ok := er.(*BodyTooLargeError) ; !ok {
+		t.Errorf("Expected a *BodyTooLargeError, got %#v", er)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`{"id": 4, "message": "woot"}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	rec := httptest.NewRecorder()
+
+	if er := jp.WriteJSON(rec, 201) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if rec.Code != 201 {
+		t.Errorf("Expected status 201, got %d", rec.Code)
+	}
+
+	if ct := rec.Header().Get("Content-Type") ; ct != "application/json" {
+		t.Errorf("Expected application/json, got %s", ct)
+	}
+
+	if body := strings.TrimSpace(rec.Body.String()) ; body != `{"id":4,"message":"woot"}` {
+		t.Errorf("Unexpected body %s", body)
+	}
+
+	rec = httptest.NewRecorder()
+
+	if er := (JPath{}).WriteJSON(rec, 200) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if body := strings.TrimSpace(rec.Body.String()) ; body != "null" {
+		t.Errorf("Expected null for a zero-value, got %s", body)
+	}
+}
+
+func TestETag(t *testing.T) {
+	var a, b, c JPath
+
+	if er := a.ParseString(`{"id": 1, "tags": ["x", "y"], "meta": {"v": 2, "w": null}}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if er := b.ParseString(`{"meta": {"w": null, "v": 2.0}, "tags": ["x", "y"], "id": 1}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if er := c.ParseString(`{"id": 1, "tags": ["y", "x"], "meta": {"v": 2, "w": null}}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	etag := a.ETag()
+
+	if len(etag) != 36 || !strings.HasPrefix(etag, `W/"`) || etag[35] != '"' {
+		t.Errorf("Expected a weak, quoted 32-digit tag, got %s", etag)
+	}
+
+	if other := b.ETag() ; other != etag {
+		t.Errorf("Expected reordered documents to share an ETag, got %s and %s", etag, other)
+	}
+
+	if other := c.ETag() ; other == etag {
+		t.Errorf("Expected different documents to have different ETags, got %s for both", etag)
+	}
+
+	dec := json.NewDecoder(strings.NewReader(`{"id": 12345678901234567890} {"id": 12345678901234567891}`))
+	dec.UseNumber()
+
+	if er := a.ParseDecoder(dec) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if er := b.ParseDecoder(dec) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if a.ETag() == b.ETag() {
+		t.Errorf("Expected big integers differing in the last digit to have different ETags")
+	}
+
+	if er := a.ParseString(`{"x": 0}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if er := b.ParseString(`{"x": -0}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if a.ETag() != b.ETag() {
+		t.Errorf("Expected 0 and -0 to share an ETag")
+	}
+}
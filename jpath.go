@@ -5,7 +5,12 @@ import (
 	"io/ioutil"
 	"fmt"
 	"math"
+	"math/big"
+	"regexp"
+	"sort"
 	"strconv"
+	"strings"
+	"unicode/utf8"
 	"encoding/json"
 )
 
@@ -24,10 +29,73 @@ type JPath struct {
 	I interface{}
 }
 
+// jsonKind returns the name of the JSON type of v: "null", "boolean", "number", "string",
+// "array" or "object". Values which have no JSON equivalent are reported as "unknown".
+func jsonKind(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64, float32, int, int32, int64, uint32, uint64, json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+
+	return "unknown"
+}
+
+// KindError is returned by ExpectArray and ExpectObject when the underlying value is of the
+// wrong JSON type.
+type KindError struct {
+	Expected string
+	Actual   string
+}
+
+func (e *KindError) Error() string {
+	return fmt.Sprintf("jpath: expected %s, got %s", e.Expected, e.Actual)
+}
+
+// ExpectArray returns a *KindError if the underlying value is not an array, and nil otherwise.
+func (jp JPath) ExpectArray() error {
+	if kind := jsonKind(jp.I) ; kind != "array" {
+		return &KindError{"array", kind}
+	}
+
+	return nil
+}
+
+// ExpectObject returns a *KindError if the underlying value is not an object, and nil otherwise.
+func (jp JPath) ExpectObject() error {
+	if kind := jsonKind(jp.I) ; kind != "object" {
+		return &KindError{"object", kind}
+	}
+
+	return nil
+}
+
+// utf8BOM is the byte order mark some tools prefix UTF-8 files with.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// stripBOM returns data without its leading UTF-8 byte order mark, if it has one.
+func stripBOM(data []byte) []byte {
+	if len(data) >= len(utf8BOM) && data[0] == utf8BOM[0] && data[1] == utf8BOM[1] && data[2] == utf8BOM[2] {
+		return data[len(utf8BOM):]
+	}
+
+	return data
+}
+
 // ParseBytes parses the bytes as JSON and overwrites the underlying value with the result.
+// A leading UTF-8 byte order mark is ignored, as is any whitespace around the value.
 func (jp *JPath) ParseBytes(bytes []byte) error {
-	jp.I = nil
-	return json.Unmarshal(bytes, &jp.I)
+	*jp = JPath{}
+	return json.Unmarshal(stripBOM(bytes), &jp.I)
 }
 
 // ParseString parses the passed string as JSON and overwrites the underlying value with the result.
@@ -45,6 +113,21 @@ func (jp *JPath) ParseReader(r io.Reader) error {
 	return jp.ParseBytes(bytes)
 }
 
+// FromStruct marshals v with encoding/json and parses the result, so typed values can be
+// navigated and transformed like any other document. As with json.Marshal, json struct tags are
+// honored.
+func FromStruct(v interface{}) (JPath, error) {
+	var jp JPath
+
+	bytes, er := json.Marshal(v)
+	if er != nil {
+		return jp, er
+	}
+
+	er = jp.ParseBytes(bytes)
+	return jp, er
+}
+
 // Length returns the length of the underlying array, or 0 if the underlying object is not an array.
 func (jp JPath) Length() int {
 	if jp.I == nil {
@@ -62,32 +145,90 @@ func (jp JPath) Length() int {
 // is not an array, it returns a zero-value JPath.
 func (jp JPath) Index(i int) JPath {
 	if jp.I == nil {
-		return jp
+		return JPath{}
 	}
 
 	ary, ok := jp.I.([]interface{})
 
 	if !ok || len(ary) <= i {
-		return JPath{nil}
+		return JPath{}
 	}
 
-	return JPath{ary[i]}
+	return JPath{I: ary[i]}
 }
 
 // Field returns a new JPath wrapping the specified field if the underlying value is an object. Otherwise,
 // it returns a zero-value JPath.
 func (jp JPath) Field(s string) JPath {
 	if jp.I == nil {
-		return jp
+		return JPath{}
 	}
 
 	obj, ok := jp.I.(map[string]interface{})
 
 	if !ok {
-		return JPath{nil}
+		return JPath{}
+	}
+
+	return JPath{I: obj[s]}
+}
+
+// HasAny returns true if the underlying object has at least one of the given keys. If the
+// underlying value is not an object, it returns false.
+func (jp JPath) HasAny(keys ...string) bool {
+	obj, ok := jp.I.(map[string]interface{})
+	if !ok {
+		return false
+	}
+
+	for _, k := range keys {
+		if _, ok := obj[k] ; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasAll returns true if the underlying object has every one of the given keys. If the
+// underlying value is not an object, it returns false.
+func (jp JPath) HasAll(keys ...string) bool {
+	obj, ok := jp.I.(map[string]interface{})
+	if !ok {
+		return false
+	}
+
+	for _, k := range keys {
+		if _, ok := obj[k] ; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// TypeCounts returns how many of the immediate children of the underlying object or array are of
+// each JSON type ("null", "boolean", "number", "string", "array" or "object"). Grandchildren are
+// not counted. For any other value, the result has a single entry for the value's own type.
+func (jp JPath) TypeCounts() map[string]int {
+	ret := map[string]int{}
+
+	switch val := jp.I.(type) {
+	case map[string]interface{}:
+		for _, child := range val {
+			ret[jsonKind(child)] += 1
+		}
+
+	case []interface{}:
+		for _, child := range val {
+			ret[jsonKind(child)] += 1
+		}
+
+	default:
+		ret[jsonKind(val)] = 1
 	}
 
-	return JPath{obj[s]}
+	return ret
 }
 
 // Fields returns a slice of strings containing the field names of the underlying object. If the underlying
@@ -108,6 +249,87 @@ func (jp JPath) Fields() []string {
 	return ret
 }
 
+// SortedFields is like Fields, but returns the field names in sorted order.
+func (jp JPath) SortedFields() []string {
+	ret := jp.Fields()
+	sort.Strings(ret)
+	return ret
+}
+
+// FieldAt returns the ith field name of the underlying object, in sorted order, along with a
+// JPath wrapping its value. If i is out of range, or the underlying value is not an object, an
+// empty string and a zero-value JPath are returned.
+func (jp JPath) FieldAt(i int) (string, JPath) {
+	fields := jp.SortedFields()
+
+	if i < 0 || i >= len(fields) {
+		return "", JPath{}
+	}
+
+	return fields[i], jp.Field(fields[i])
+}
+
+// Entries returns the sorted field names of the underlying object alongside JPaths wrapping
+// their values, such that values[i] is the value of keys[i]. If the underlying value is not an
+// object, both slices are empty.
+func (jp JPath) Entries() (keys []string, values []JPath) {
+	keys = jp.SortedFields()
+	values = make([]JPath, len(keys))
+
+	for i, k := range keys {
+		values[i] = jp.Field(k)
+	}
+
+	return keys, values
+}
+
+// EachFieldOrdered calls fn for each field of the underlying object: first those named in order,
+// in that order, then any remaining fields in sorted order. Names in order which aren't fields of
+// the object are skipped, and no field is visited twice. If the underlying value is not an
+// object, fn is never called.
+func (jp JPath) EachFieldOrdered(order []string, fn func(key string, v JPath)) {
+	obj, ok := jp.I.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	visited := make(map[string]bool, len(obj))
+
+	for _, k := range order {
+		if _, present := obj[k] ; present && !visited[k] {
+			visited[k] = true
+			fn(k, jp.Field(k))
+		}
+	}
+
+	for _, k := range jp.SortedFields() {
+		if !visited[k] {
+			fn(k, jp.Field(k))
+		}
+	}
+}
+
+// FieldsRegex returns the sorted field names of the underlying object which match the regular
+// expression pattern. If the underlying value is not an object, returns an empty slice. An error
+// is only returned if the pattern fails to compile.
+func (jp JPath) FieldsRegex(pattern string) ([]string, error) {
+	re, er := regexp.Compile(pattern)
+	if er != nil {
+		return nil, er
+	}
+
+	ret := []string{}
+
+	for _, k := range jp.Fields() {
+		if re.MatchString(k) {
+			ret = append(ret, k)
+		}
+	}
+
+	sort.Strings(ret)
+	return ret, nil
+}
+
 // String does its best to convert the underlying value to a string. For zero-value JPath objects
 // and JPath objects which wrap an object or array, String returns an empty string.
 func (jp JPath) String() string {
@@ -135,9 +357,222 @@ func (jp JPath) String() string {
 		return fmt.Sprintf("%f", num)
 	}
 
+	if num, ok := jp.I.(json.Number) ; ok {
+		return num.String()
+	}
+
 	return ""
 }
 
+// StringOrField handles values which are sometimes a scalar and sometimes an object wrapping
+// one. If the underlying value is an object, it returns the String of its fieldName field;
+// otherwise it returns the String of the value itself.
+func (jp JPath) StringOrField(fieldName string) string {
+	if _, ok := jp.I.(map[string]interface{}) ; ok {
+		return jp.Field(fieldName).String()
+	}
+
+	return jp.String()
+}
+
+// StringBytes is like String, but returns a byte slice, which the caller is free to modify. If the
+// underlying value is a string, the slice is a copy of it. Numbers are formatted with strconv
+// rather than fmt to avoid the intermediate allocations.
+func (jp JPath) StringBytes() []byte {
+	if jp.I == nil {
+		return []byte{}
+	}
+
+	if str, ok := jp.I.(string) ; ok {
+		return []byte(str)
+	}
+
+	if num, ok := jp.I.(int) ; ok {
+		return strconv.AppendInt(nil, int64(num), 10)
+	}
+
+	if num, ok := jp.I.(int32) ; ok {
+		return strconv.AppendInt(nil, int64(num), 10)
+	}
+
+	if num, ok := jp.I.(uint32) ; ok {
+		return strconv.AppendUint(nil, uint64(num), 10)
+	}
+
+	if num, ok := jp.I.(float64) ; ok {
+		return strconv.AppendFloat(nil, num, 'f', 6, 64)
+	}
+
+	if num, ok := jp.I.(json.Number) ; ok {
+		return []byte(num)
+	}
+
+	return []byte{}
+}
+
+// Rune returns the first rune of the underlying string, or the rune with the underlying number's
+// code point. Numbers which aren't valid code points yield utf8.RuneError. Empty strings,
+// objects, arrays and anything else yield 0.
+func (jp JPath) Rune() rune {
+	if str, ok := jp.I.(string) ; ok {
+		if str == "" {
+			return 0
+		}
+
+		r, _ := utf8.DecodeRuneInString(str)
+		return r
+	}
+
+	if num, ok := jsonNumber(jp.I) ; ok {
+		if num != math.Trunc(num) || num < 0 || num > utf8.MaxRune || !utf8.ValidRune(rune(num)) {
+			return utf8.RuneError
+		}
+
+		return rune(num)
+	}
+
+	return 0
+}
+
+// UUID parses the underlying string as a UUID in its canonical 8-4-4-4-12 hexadecimal form, such
+// as "123e4567-e89b-12d3-a456-426614174000". Either case of hex digit is accepted. If the
+// underlying value is not a string, or is not in that form, a zero array and false are returned.
+func (jp JPath) UUID() ([16]byte, bool) {
+	var ret [16]byte
+
+	str, ok := jp.I.(string)
+	if !ok || len(str) != 36 {
+		return [16]byte{}, false
+	}
+
+	for i, j := 0, 0 ; i < len(str) ; j += 1 {
+		if i == 8 || i == 13 || i == 18 || i == 23 {
+			if str[i] != '-' {
+				return [16]byte{}, false
+			}
+
+			i += 1
+		}
+
+		hi, lo := hexDigit(str[i]), hexDigit(str[i + 1])
+		if hi < 0 || lo < 0 {
+			return [16]byte{}, false
+		}
+
+		ret[j] = byte(hi << 4 | lo)
+		i += 2
+	}
+
+	return ret, true
+}
+
+// hexDigit returns the value of the hexadecimal digit c, or -1 if it isn't one.
+func hexDigit(c byte) int {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0')
+	case c >= 'a' && c <= 'f':
+		return int(c - 'a') + 10
+	case c >= 'A' && c <= 'F':
+		return int(c - 'A') + 10
+	}
+
+	return -1
+}
+
+// Enum returns the value coerced by String, and true, if it exactly matches one of allowed.
+// Otherwise, it returns an empty string and false.
+func (jp JPath) Enum(allowed ...string) (string, bool) {
+	str := jp.String()
+
+	for _, a := range allowed {
+		if str == a {
+			return str, true
+		}
+	}
+
+	return "", false
+}
+
+// EnumFold is like Enum, but matches case-insensitively. The matching entry of allowed is
+// returned, rather than the value as it appears in the document.
+func (jp JPath) EnumFold(allowed ...string) (string, bool) {
+	str := jp.String()
+
+	for _, a := range allowed {
+		if strings.EqualFold(str, a) {
+			return a, true
+		}
+	}
+
+	return "", false
+}
+
+// TypedValue handles self-describing objects such as {"value": "42", "type": "int"}. It reads
+// the type name from the typeField field and returns the "value" field coerced accordingly:
+// "int" or "integer" with Int, "float" or "number" with Float64, "bool" or "boolean" with Bool,
+// and "string" with String. Type names are case-insensitive. For any other type name, the value
+// is returned uncoerced. If the underlying value is not an object, nil is returned.
+func (jp JPath) TypedValue(typeField string) interface{} {
+	if _, ok := jp.I.(map[string]interface{}) ; !ok {
+		return nil
+	}
+
+	value := jp.Field("value")
+
+	switch strings.ToLower(jp.Field(typeField).String()) {
+	case "int", "integer":
+		return value.Int()
+	case "float", "number":
+		return value.Float64()
+	case "bool", "boolean":
+		return value.Bool()
+	case "string":
+		return value.String()
+	}
+
+	return value.I
+}
+
+// ParseWith passes the underlying string through fn, returning its result, so that
+// domain-specific formats can be parsed at the point of extraction. If the underlying value is
+// not a string, or fn returns an error, nil is returned.
+func (jp JPath) ParseWith(fn func(string) (interface{}, error)) interface{} {
+	str, ok := jp.I.(string)
+	if !ok {
+		return nil
+	}
+
+	ret, er := fn(str)
+	if er != nil {
+		return nil
+	}
+
+	return ret
+}
+
+// Matches reports whether the value, coerced by String, matches the regular expression pattern
+// in its entirety. It returns false for values which String can't represent (null, booleans,
+// objects and arrays) and for invalid patterns; use MatchesErr to tell the latter apart.
+func (jp JPath) Matches(pattern string) bool {
+	ok, _ := jp.MatchesErr(pattern)
+	return ok
+}
+
+// MatchesErr is like Matches, but returns an error if pattern fails to compile.
+func (jp JPath) MatchesErr(pattern string) (bool, error) {
+	re, er := regexp.Compile(`^(?:` + pattern + `)$`)
+	if er != nil {
+		return false, er
+	}
+
+	if kind := jsonKind(jp.I) ; kind != "string" && kind != "number" {
+		return false, nil
+	}
+
+	return re.MatchString(jp.String()), nil
+}
+
 // StringMap returns a map[string]string. If the underlying value is an object, the returned map
 // consists of any fields that are strings. Otherwise, an empty map is returned. Any non-string
 // values are coerced to strings.
@@ -167,6 +602,11 @@ func (jp JPath) Float64() float64 {
 		return num
 	}
 
+	if num, ok := jp.I.(json.Number) ; ok {
+		fval, _ := num.Float64()
+		return fval
+	}
+
 	if num, ok := jp.I.(int) ; ok {
 		return float64(num)
 	}
@@ -193,8 +633,14 @@ func (jp JPath) Float32() float32 {
 }
 
 // Int64 casts the return value of Float64 (all JSON numerics are encoded as doubles). 
-// NaN float values are considered 0.
+// NaN float values are considered 0. Integral json.Number values are converted exactly.
 func (jp JPath) Int64() int64 {
+	if num, ok := jp.I.(json.Number) ; ok {
+		if ival, er := num.Int64() ; er == nil {
+			return ival
+		}
+	}
+
 	fval := jp.Float64()
 
 	if math.IsNaN(fval) {
@@ -204,6 +650,169 @@ func (jp JPath) Int64() int64 {
 	return int64(fval)
 }
 
+// Int64Base is like Int64, but parses string values as integers in the given base using
+// strconv.ParseInt. A base of 0 infers the base from the string's prefix ("0x", "0o", "0b").
+// Strings which don't parse yield 0; values which aren't strings are handled by Int64.
+func (jp JPath) Int64Base(base int) int64 {
+	str, ok := jp.I.(string)
+	if !ok {
+		return jp.Int64()
+	}
+
+	num, er := strconv.ParseInt(str, base, 64)
+	if er != nil {
+		return 0
+	}
+
+	return num
+}
+
+// BigInt returns the underlying value as an arbitrary-precision integer. Strings are parsed in
+// base 10, or failing that with their base inferred from a "0x", "0o" or "0b" prefix. Numbers
+// are converted exactly: json.Number values keep every digit, and float64 values are converted
+// if they have no fractional part. For anything else, including non-integer strings and
+// numbers, nil is returned.
+func (jp JPath) BigInt() *big.Int {
+	var str string
+
+	switch val := jp.I.(type) {
+	case string:
+		str = val
+
+	case json.Number:
+		str = val.String()
+
+	default:
+		num, ok := jsonNumber(val)
+		if !ok || math.IsInf(num, 0) || num != math.Trunc(num) {
+			return nil
+		}
+
+		ret, _ := big.NewFloat(num).Int(nil)
+		return ret
+	}
+
+	if ret, ok := new(big.Int).SetString(str, 10) ; ok {
+		return ret
+	}
+
+	if ret, ok := new(big.Int).SetString(str, 0) ; ok {
+		return ret
+	}
+
+	return nil
+}
+
+// CoercionError is returned by the *Exact coercions when the underlying value can't be
+// converted without losing information.
+type CoercionError struct {
+	Value  interface{}
+	Target string
+	Reason string
+}
+
+func (e *CoercionError) Error() string {
+	return fmt.Sprintf("jpath: cannot convert %#v to %s: %s", e.Value, e.Target, e.Reason)
+}
+
+// Float64Exact is a strict version of Float64. It returns a *CoercionError, rather than 0, if
+// the underlying value is not a number or a string which parses entirely as one, if it is out
+// of the range of a float64, or if it is NaN or infinite (including strings like "NaN" and
+// "Inf", which IsNumericString likewise rejects).
+func (jp JPath) Float64Exact() (float64, error) {
+	var str string
+
+	switch val := jp.I.(type) {
+	case string:
+		str = val
+
+	case json.Number:
+		str = val.String()
+
+	default:
+		num, ok := jsonNumber(val)
+		if !ok {
+			return 0, &CoercionError{jp.I, "float64", "not a number"}
+		}
+
+		if math.IsNaN(num) || math.IsInf(num, 0) {
+			return 0, &CoercionError{jp.I, "float64", "not finite"}
+		}
+
+		return num, nil
+	}
+
+	num, er := strconv.ParseFloat(str, 64)
+	if er != nil {
+		if numEr, ok := er.(*strconv.NumError) ; ok && numEr.Err == strconv.ErrRange {
+			return 0, &CoercionError{jp.I, "float64", "out of range"}
+		}
+
+		return 0, &CoercionError{jp.I, "float64", "not a number"}
+	}
+
+	if math.IsNaN(num) || math.IsInf(num, 0) {
+		return 0, &CoercionError{jp.I, "float64", "not finite"}
+	}
+
+	return num, nil
+}
+
+// Int64Exact is a strict version of Int64. It returns a *CoercionError, rather than a truncated
+// or zero value, if the underlying value is not an integer (including numbers with a fractional
+// part), or if it is out of the range of an int64.
+func (jp JPath) Int64Exact() (int64, error) {
+	var str string
+
+	switch val := jp.I.(type) {
+	case string:
+		str = val
+	case json.Number:
+		str = val.String()
+	}
+
+	if str != "" {
+		if num, er := strconv.ParseInt(str, 10, 64) ; er == nil {
+			return num, nil
+		}
+	}
+
+	fval, er := jp.Float64Exact()
+	if er != nil {
+		return 0, &CoercionError{jp.I, "int64", er.(*CoercionError).Reason}
+	}
+
+	// fval may have been rounded, so decide strings and json.Numbers from their exact decimal
+	// value instead.
+	if str != "" {
+		rat, ok := new(big.Rat).SetString(str)
+		if !ok {
+			return 0, &CoercionError{jp.I, "int64", "not a number"}
+		}
+
+		if !rat.IsInt() {
+			return 0, &CoercionError{jp.I, "int64", "not an integer"}
+		}
+
+		if !rat.Num().IsInt64() {
+			return 0, &CoercionError{jp.I, "int64", "out of range"}
+		}
+
+		return rat.Num().Int64(), nil
+	}
+
+	if fval != math.Trunc(fval) {
+		return 0, &CoercionError{jp.I, "int64", "not an integer"}
+	}
+
+	// float64(math.MaxInt64) rounds up to 2^63, which is itself out of range.
+	if fval < math.MinInt64 || fval >= math.MaxInt64 {
+		return 0, &CoercionError{jp.I, "int64", "out of range"}
+	}
+
+	return int64(fval), nil
+}
+
 // Int32 casts the return value of Int64
 func (jp JPath) Int32() int32 {
 	return int32(jp.Int64())
@@ -225,7 +834,14 @@ func (jp JPath) Int() int {
 }
 
 // Uint64 casts the return value of Float64 (all JSON numerics are encoded as doubles).
+// Integral json.Number values are converted exactly.
 func (jp JPath) Uint64() uint64 {
+	if num, ok := jp.I.(json.Number) ; ok {
+		if uval, er := strconv.ParseUint(num.String(), 10, 64) ; er == nil {
+			return uval
+		}
+	}
+
 	fval := jp.Float64()
 
 	if math.IsNaN(fval) {
@@ -255,6 +871,200 @@ func (jp JPath) Uint() uint {
 	return uint(jp.Uint64())
 }
 
+// Bool returns a bool representation of the underlying value. Numbers are true when non-zero,
+// and strings are parsed with strconv.ParseBool ("1", "t", "true", "TRUE", ...). Everything
+// else, including strings which don't parse, is false.
+func (jp JPath) Bool() bool {
+	if b, ok := jp.I.(bool) ; ok {
+		return b
+	}
+
+	if num, ok := jsonNumber(jp.I) ; ok {
+		return num != 0 && !math.IsNaN(num)
+	}
+
+	if str, ok := jp.I.(string) ; ok {
+		b, _ := strconv.ParseBool(str)
+		return b
+	}
+
+	return false
+}
+
+// BoolWith is like Bool, but first compares an underlying string against truthy and falsy,
+// ignoring case, so domain-specific spellings such as "on"/"off" or "Y"/"N" can be recognised.
+// A string found in truthy yields true, and one found in falsy yields false; a string in both is
+// treated as truthy. Any other value falls back to Bool.
+func (jp JPath) BoolWith(truthy, falsy []string) bool {
+	if str, ok := jp.I.(string) ; ok {
+		for _, t := range truthy {
+			if strings.EqualFold(str, t) {
+				return true
+			}
+		}
+
+		for _, f := range falsy {
+			if strings.EqualFold(str, f) {
+				return false
+			}
+		}
+	}
+
+	return jp.Bool()
+}
+
+// IntPtr returns a pointer to the result of Int, or nil if the underlying value is null.
+func (jp JPath) IntPtr() *int {
+	if jp.I == nil {
+		return nil
+	}
+
+	ret := jp.Int()
+	return &ret
+}
+
+// StringPtr returns a pointer to the result of String, or nil if the underlying value is null.
+func (jp JPath) StringPtr() *string {
+	if jp.I == nil {
+		return nil
+	}
+
+	ret := jp.String()
+	return &ret
+}
+
+// BoolPtr returns a pointer to the result of Bool, or nil if the underlying value is null.
+func (jp JPath) BoolPtr() *bool {
+	if jp.I == nil {
+		return nil
+	}
+
+	ret := jp.Bool()
+	return &ret
+}
+
+// Float64Ptr returns a pointer to the result of Float64, or nil if the underlying value is null.
+func (jp JPath) Float64Ptr() *float64 {
+	if jp.I == nil {
+		return nil
+	}
+
+	ret := jp.Float64()
+	return &ret
+}
+
+// AsString returns the underlying value and true if it is a string, without any coercion.
+func (jp JPath) AsString() (string, bool) {
+	str, ok := jp.I.(string)
+	return str, ok
+}
+
+// AsFloat64 returns the underlying value and true if it is a float64, without any coercion.
+func (jp JPath) AsFloat64() (float64, bool) {
+	num, ok := jp.I.(float64)
+	return num, ok
+}
+
+// AsBool returns the underlying value and true if it is a bool, without any coercion.
+func (jp JPath) AsBool() (bool, bool) {
+	b, ok := jp.I.(bool)
+	return b, ok
+}
+
+// AsMap returns the underlying value and true if it is an object, without any coercion. The map
+// is not copied.
+func (jp JPath) AsMap() (map[string]interface{}, bool) {
+	obj, ok := jp.I.(map[string]interface{})
+	return obj, ok
+}
+
+// AsSlice returns the underlying value and true if it is an array, without any coercion. The
+// slice is not copied.
+func (jp JPath) AsSlice() ([]interface{}, bool) {
+	ary, ok := jp.I.([]interface{})
+	return ary, ok
+}
+
+// IsNumericString returns true if the underlying value is a string which parses as a finite
+// number, such as "3" or "05". Strings like "NaN" and "Inf" are not considered numeric.
+func (jp JPath) IsNumericString() bool {
+	str, ok := jp.I.(string)
+	if !ok {
+		return false
+	}
+
+	num, er := strconv.ParseFloat(str, 64)
+	return er == nil && !math.IsNaN(num) && !math.IsInf(num, 0)
+}
+
+// NumberWithSuffix splits an underlying string such as "85%" or "1.5x" into its leading number
+// and the rest of the string, so "85%" yields (85, "%"). Whitespace between the number and the
+// suffix is dropped. Strings which don't start with a number yield 0 and the whole string. For
+// values which aren't strings, the value coerced by Float64 and "" are returned.
+func (jp JPath) NumberWithSuffix() (float64, string) {
+	str, ok := jp.I.(string)
+	if !ok {
+		return jp.Float64(), ""
+	}
+
+	digits := func(i int) int {
+		for i < len(str) && str[i] >= '0' && str[i] <= '9' {
+			i += 1
+		}
+
+		return i
+	}
+
+	i := 0
+	if i < len(str) && (str[i] == '+' || str[i] == '-') {
+		i += 1
+	}
+
+	end := digits(i)
+	if end < len(str) && str[end] == '.' {
+		end = digits(end + 1)
+	}
+
+	// The mantissa needs at least one digit; "." and "-" alone aren't numbers.
+	if end == i || (end == i + 1 && str[i] == '.') {
+		return 0, str
+	}
+
+	// Only treat an 'e' as an exponent if digits follow it, so "5em" splits as (5, "em").
+	if end < len(str) && (str[end] == 'e' || str[end] == 'E') {
+		exp := end + 1
+		if exp < len(str) && (str[exp] == '+' || str[exp] == '-') {
+			exp += 1
+		}
+
+		if expEnd := digits(exp) ; expEnd > exp {
+			end = expEnd
+		}
+	}
+
+	num, er := strconv.ParseFloat(str[:end], 64)
+	if er != nil {
+		return 0, str
+	}
+
+	return num, strings.TrimSpace(str[end:])
+}
+
+// WasNumber returns true if the underlying value is a genuine number, rather than a string
+// which happens to look like one.
+func (jp JPath) WasNumber() bool {
+	_, ok := jsonNumber(jp.I)
+	return ok
+}
+
+// Debug returns a one-line, human-readable description of the underlying value: its Go type,
+// its JSON type, and what each of the main coercions makes of it. It is meant as a diagnostic
+// for surprising coercion results, and its format may change.
+func (jp JPath) Debug() string {
+	return fmt.Sprintf("type=%T kind=%s Int64=%d Float64=%g Bool=%t String=%q",
+		jp.I, jsonKind(jp.I), jp.Int64(), jp.Float64(), jp.Bool(), jp.String())
+}
+
 // IsNull returns true if the underlying value was a JSON null, or if this JPath is a zero-value.
 func (jp JPath) IsNull() bool {
 	return jp.I == nil
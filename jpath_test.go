@@ -1,6 +1,14 @@
 package jpath
 
-import "testing"
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"reflect"
+	"testing"
+	"unicode/utf8"
+)
 
 func TestBigIDs(t *testing.T) {
 	var jp JPath
@@ -63,3 +71,795 @@ func TestObjectFields(t *testing.T) {
 		}
 	}
 }
+
+func TestStringBytes(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`{"str": "hello", "num": 42.5}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if str := string(jp.Field("str").StringBytes()) ; str != "hello" {
+		t.Errorf("Expected hello, got %s", str)
+	}
+
+	num := jp.Field("num")
+	if str := string(num.StringBytes()) ; str != num.String() {
+		t.Errorf("Expected %s, got %s", num.String(), str)
+	}
+
+	bytes := jp.Field("str").StringBytes()
+	bytes[0] = 'j'
+
+	if str := jp.Field("str").String() ; str != "hello" {
+		t.Errorf("Expected modifying the result to leave the document alone, got %s", str)
+	}
+}
+
+func BenchmarkString(b *testing.B) {
+	jp := JPath{I: "some moderately long string value"}
+
+	for i := 0 ; i < b.N ; i += 1 {
+		_ = jp.String()
+	}
+}
+
+// BenchmarkStringBytes measures the copy StringBytes makes of a string, which String avoids.
+func BenchmarkStringBytes(b *testing.B) {
+	jp := JPath{I: "some moderately long string value"}
+
+	b.ReportAllocs()
+
+	for i := 0 ; i < b.N ; i += 1 {
+		_ = jp.StringBytes()
+	}
+}
+
+func TestFieldsRegex(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`{"metric.cpu.0": 1, "metric.cpu.1": 2, "metric.mem": 3, "host": "a"}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	fields, er := jp.FieldsRegex(`^metric\.cpu\.\d+$`)
+	if er != nil {
+		t.Fatal(er)
+	}
+
+	if len(fields) != 2 || fields[0] != "metric.cpu.0" || fields[1] != "metric.cpu.1" {
+		t.Errorf("Unexpected fields %v", fields)
+	}
+
+	if _, er := jp.FieldsRegex(`metric(`) ; er == nil {
+		t.Errorf("Expected an error for an invalid pattern")
+	}
+
+	fields, er = jp.Field("host").FieldsRegex(`.*`)
+	if er != nil || len(fields) != 0 {
+		t.Errorf("Expected no fields for a non-object, got %v (%v)", fields, er)
+	}
+}
+
+func TestNumericString(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`[3, "3", "05", "NaN", "three"]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	expected := []struct{ numericString, wasNumber bool }{
+		{false, true},
+		{true, false},
+		{true, false},
+		{false, false},
+		{false, false},
+	}
+
+	for i, exp := range expected {
+		elem := jp.Index(i)
+
+		if elem.IsNumericString() != exp.numericString {
+			t.Errorf("Expected IsNumericString of %#v to be %v", elem.I, exp.numericString)
+		}
+
+		if elem.WasNumber() != exp.wasNumber {
+			t.Errorf("Expected WasNumber of %#v to be %v", elem.I, exp.wasNumber)
+		}
+	}
+}
+
+func TestNumberWithSuffix(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`["85%", "1.5x", "-2.5e3 ms", "5em", "12", "n/a", ".", 42]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	expected := []struct {
+		num    float64
+		suffix string
+	}{
+		{85, "%"},
+		{1.5, "x"},
+		{-2500, "ms"},
+		{5, "em"},
+		{12, ""},
+		{0, "n/a"},
+		{0, "."},
+		{42, ""},
+	}
+
+	for i, exp := range expected {
+		if num, suffix := jp.Index(i).NumberWithSuffix() ; num != exp.num || suffix != exp.suffix {
+			t.Errorf("Expected (%v, %q) for %#v, got (%v, %q)", exp.num, exp.suffix, jp.Index(i).I, num, suffix)
+		}
+	}
+}
+
+func TestRune(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`["A", "", 955, 1.5, {"flag": "Y"}]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	expected := []rune{'A', 0, 'λ', utf8.RuneError, 0}
+
+	for i, r := range expected {
+		if actual := jp.Index(i).Rune() ; actual != r {
+			t.Errorf("Expected %q for %#v, got %q", r, jp.Index(i).I, actual)
+		}
+	}
+}
+
+func TestUUID(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`["123e4567-E89B-12d3-a456-426614174000", "123e4567e89b-12d3-a456-426614174000-", "123e4567-e89b-12d3-a456-42661417400g", "123e4567-e89b-12d3-a456", 42]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	expected := [16]byte{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00}
+
+	if actual, ok := jp.Index(0).UUID() ; !ok || actual != expected {
+		t.Errorf("Expected %x, got %x (%v)", expected, actual, ok)
+	}
+
+	for i := 1 ; i < 5 ; i += 1 {
+		if actual, ok := jp.Index(i).UUID() ; ok || actual != [16]byte{} {
+			t.Errorf("Expected element %d not to parse, got %x", i, actual)
+		}
+	}
+}
+
+func TestEnum(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`["active", "ACTIVE", "deleted"]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	allowed := []string{"active", "suspended"}
+
+	if val, ok := jp.Index(0).Enum(allowed...) ; !ok || val != "active" {
+		t.Errorf("Expected active to be allowed, got %q %v", val, ok)
+	}
+
+	if val, ok := jp.Index(1).Enum(allowed...) ; ok || val != "" {
+		t.Errorf("Expected ACTIVE to be rejected by Enum, got %q %v", val, ok)
+	}
+
+	if val, ok := jp.Index(1).EnumFold(allowed...) ; !ok || val != "active" {
+		t.Errorf("Expected ACTIVE to be allowed by EnumFold, got %q %v", val, ok)
+	}
+
+	if val, ok := jp.Index(2).EnumFold(allowed...) ; ok || val != "" {
+		t.Errorf("Expected deleted to be rejected, got %q %v", val, ok)
+	}
+}
+
+func TestEachFieldOrdered(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`{"email": "bob@example.com", "id": 7, "zone": "eu", "name": "bob", "age": 30}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	keys := []string{}
+
+	jp.EachFieldOrdered([]string{"name", "missing", "id", "name"}, func(key string, v JPath) {
+		keys = append(keys, key)
+
+		if !reflect.DeepEqual(v.I, jp.Field(key).I) {
+			t.Errorf("Expected the value of %s, got %#v", key, v.I)
+		}
+	})
+
+	if fmt.Sprint(keys) != "[name id age email zone]" {
+		t.Errorf("Expected [name id age email zone], got %v", keys)
+	}
+
+	jp.Field("id").EachFieldOrdered([]string{"id"}, func(key string, v JPath) {
+		t.Errorf("Expected no calls for a non-object, got %s", key)
+	})
+}
+
+func TestFieldAt(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`{"charlie": 3, "alpha": 1, "bravo": 2}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if name, val := jp.FieldAt(0) ; name != "alpha" || val.Int() != 1 {
+		t.Errorf("Expected alpha=1, got %s=%v", name, val.I)
+	}
+
+	if name, val := jp.FieldAt(2) ; name != "charlie" || val.Int() != 3 {
+		t.Errorf("Expected charlie=3, got %s=%v", name, val.I)
+	}
+
+	if name, val := jp.FieldAt(3) ; name != "" || !val.IsNull() {
+		t.Errorf("Expected nothing out of range, got %s=%v", name, val.I)
+	}
+
+	if name, val := jp.Field("alpha").FieldAt(0) ; name != "" || !val.IsNull() {
+		t.Errorf("Expected nothing for a non-object, got %s=%v", name, val.I)
+	}
+}
+
+func TestBool(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`[true, false, 1, 0, "true", "F", "yes", null, {}]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	expected := []bool{true, false, true, false, true, false, false, false, false}
+
+	for i, b := range expected {
+		if actual := jp.Index(i).Bool() ; actual != b {
+			t.Errorf("Expected %v for %#v, got %v", b, jp.Index(i).I, actual)
+		}
+	}
+}
+
+func TestBoolWith(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`["on", "OFF", "Enabled", "true", "1", "0", "maybe", 2, false]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	truthy, falsy := []string{"on", "enabled"}, []string{"off", "disabled", "true"}
+	expected := []bool{true, false, true, false, true, false, false, true, false}
+
+	for i, b := range expected {
+		if actual := jp.Index(i).BoolWith(truthy, falsy) ; actual != b {
+			t.Errorf("Expected %v for %#v, got %v", b, jp.Index(i).I, actual)
+		}
+	}
+}
+
+func TestPointers(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`{"count": 5, "zero": 0, "name": "x", "empty": "", "on": true, "off": false, "ratio": 0.5, "none": null}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if ptr := jp.Field("none").IntPtr() ; ptr != nil {
+		t.Errorf("Expected nil for null, got %d", *ptr)
+	}
+
+	if ptr := jp.Field("missing").StringPtr() ; ptr != nil {
+		t.Errorf("Expected nil for a missing field, got %q", *ptr)
+	}
+
+	if ptr := jp.Field("none").BoolPtr() ; ptr != nil {
+		t.Errorf("Expected nil for null, got %v", *ptr)
+	}
+
+	if ptr := jp.Field("none").Float64Ptr() ; ptr != nil {
+		t.Errorf("Expected nil for null, got %f", *ptr)
+	}
+
+	if ptr := jp.Field("count").IntPtr() ; ptr == nil || *ptr != 5 {
+		t.Errorf("Expected a pointer to 5, got %v", ptr)
+	}
+
+	if ptr := jp.Field("zero").IntPtr() ; ptr == nil || *ptr != 0 {
+		t.Errorf("Expected a pointer to 0, got %v", ptr)
+	}
+
+	if ptr := jp.Field("name").StringPtr() ; ptr == nil || *ptr != "x" {
+		t.Errorf("Expected a pointer to x, got %v", ptr)
+	}
+
+	if ptr := jp.Field("empty").StringPtr() ; ptr == nil || *ptr != "" {
+		t.Errorf("Expected a pointer to an empty string, got %v", ptr)
+	}
+
+	if ptr := jp.Field("on").BoolPtr() ; ptr == nil || !*ptr {
+		t.Errorf("Expected a pointer to true, got %v", ptr)
+	}
+
+	if ptr := jp.Field("off").BoolPtr() ; ptr == nil || *ptr {
+		t.Errorf("Expected a pointer to false, got %v", ptr)
+	}
+
+	if ptr := jp.Field("ratio").Float64Ptr() ; ptr == nil || *ptr != 0.5 {
+		t.Errorf("Expected a pointer to 0.5, got %v", ptr)
+	}
+}
+
+func TestAsAccessors(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`{"str": "3", "num": 3, "bool": true, "obj": {"a": 1}, "ary": [1]}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if str, ok := jp.Field("str").AsString() ; !ok || str != "3" {
+		t.Errorf("Expected AsString to return 3, got %q %v", str, ok)
+	}
+
+	if _, ok := jp.Field("num").AsString() ; ok {
+		t.Errorf("Expected AsString of a number to fail")
+	}
+
+	if num, ok := jp.Field("num").AsFloat64() ; !ok || num != 3 {
+		t.Errorf("Expected AsFloat64 to return 3, got %f %v", num, ok)
+	}
+
+	if _, ok := jp.Field("str").AsFloat64() ; ok {
+		t.Errorf("Expected AsFloat64 of a string to fail")
+	}
+
+	if b, ok := jp.Field("bool").AsBool() ; !ok || !b {
+		t.Errorf("Expected AsBool to return true, got %v %v", b, ok)
+	}
+
+	if _, ok := jp.Field("num").AsBool() ; ok {
+		t.Errorf("Expected AsBool of a number to fail")
+	}
+
+	if obj, ok := jp.Field("obj").AsMap() ; !ok || len(obj) != 1 {
+		t.Errorf("Expected AsMap to return the object, got %v %v", obj, ok)
+	}
+
+	if _, ok := jp.Field("ary").AsMap() ; ok {
+		t.Errorf("Expected AsMap of an array to fail")
+	}
+
+	if ary, ok := jp.Field("ary").AsSlice() ; !ok || len(ary) != 1 {
+		t.Errorf("Expected AsSlice to return the array, got %v %v", ary, ok)
+	}
+
+	if _, ok := jp.Field("obj").AsSlice() ; ok {
+		t.Errorf("Expected AsSlice of an object to fail")
+	}
+}
+
+func TestInt64Base(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`["ff", "0x1F", "zz", 12]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if val := jp.Index(0).Int64Base(16) ; val != 255 {
+		t.Errorf("Expected 255, got %d", val)
+	}
+
+	if val := jp.Index(1).Int64Base(0) ; val != 31 {
+		t.Errorf("Expected 31, got %d", val)
+	}
+
+	if val := jp.Index(2).Int64Base(16) ; val != 0 {
+		t.Errorf("Expected 0, got %d", val)
+	}
+
+	if val := jp.Index(3).Int64Base(16) ; val != 12 {
+		t.Errorf("Expected numbers to be unaffected by base, got %d", val)
+	}
+}
+
+func TestHasAnyAll(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`{"status": "error", "error": "bad auth"}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if !jp.HasAny("data", "error") {
+		t.Errorf("Expected HasAny to find error")
+	}
+
+	if jp.HasAll("status", "data") {
+		t.Errorf("Expected HasAll to miss data")
+	}
+
+	if !jp.HasAll("status", "error") {
+		t.Errorf("Expected HasAll to find status and error")
+	}
+
+	if jp.Field("status").HasAny("status") || jp.Field("status").HasAll() {
+		t.Errorf("Expected false for a non-object")
+	}
+}
+
+func TestExpectKind(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`{"items": [1, 2]}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if er := jp.ExpectObject() ; er != nil {
+		t.Errorf("Expected no error for an object, got %v", er)
+	}
+
+	if er := jp.Field("items").ExpectArray() ; er != nil {
+		t.Errorf("Expected no error for an array, got %v", er)
+	}
+
+	er := jp.ExpectArray()
+	if kindEr, ok := er.(*KindError) ; !ok || kindEr.Expected != "array" || kindEr.Actual != "object" {
+		t.Errorf("Expected a *KindError for an object, got %#v", er)
+	}
+
+	if er := jp.Field("missing").ExpectObject() ; er == nil || er.Error() != "jpath: expected object, got null" {
+		t.Errorf("Expected a descriptive error for null, got %v", er)
+	}
+}
+
+func TestStringOrField(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`["plain", {"value": "wrapped"}, {"other": "x"}]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	expected := []string{"plain", "wrapped", ""}
+
+	for i, str := range expected {
+		if actual := jp.Index(i).StringOrField("value") ; actual != str {
+			t.Errorf("Expected %q, got %q", str, actual)
+		}
+	}
+}
+
+func TestBigInt(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`["1234567890123456789012345678901234567890", 42, "0x10", 1.5, "12abc"]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if val := jp.Index(0).BigInt() ; val == nil || val.String() != "1234567890123456789012345678901234567890" {
+		t.Errorf("Expected the 40-digit integer, got %v", val)
+	}
+
+	if val := jp.Index(1).BigInt() ; val == nil || val.Int64() != 42 {
+		t.Errorf("Expected 42, got %v", val)
+	}
+
+	if val := jp.Index(2).BigInt() ; val == nil || val.Int64() != 16 {
+		t.Errorf("Expected 16, got %v", val)
+	}
+
+	if val := jp.Index(3).BigInt() ; val != nil {
+		t.Errorf("Expected nil for a fractional number, got %v", val)
+	}
+
+	if val := jp.Index(4).BigInt() ; val != nil {
+		t.Errorf("Expected nil for a non-numeric string, got %v", val)
+	}
+
+	if val := (JPath{I: json.Number("98765432109876543210")}).BigInt() ; val == nil || val.String() != "98765432109876543210" {
+		t.Errorf("Expected a json.Number to convert exactly, got %v", val)
+	}
+}
+
+func TestTypedValue(t *testing.T) {
+	var jp JPath
+	jsonBlob := `[
+		{"value": "42", "type": "int"},
+		{"value": "2.5", "type": "float"},
+		{"value": "true", "type": "bool"},
+		{"value": "seven", "type": "STRING"},
+		{"value": "x", "type": "unknown"}
+	]`
+
+	if er := jp.ParseString(jsonBlob) ; er != nil {
+		t.Fatal(er)
+	}
+
+	expected := []interface{}{42, 2.5, true, "seven", "x"}
+
+	for i, val := range expected {
+		if actual := jp.Index(i).TypedValue("type") ; actual != val {
+			t.Errorf("Expected %#v, got %#v", val, actual)
+		}
+	}
+
+	if val := jp.TypedValue("type") ; val != nil {
+		t.Errorf("Expected nil for a non-object, got %#v", val)
+	}
+}
+
+func TestParseWith(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`["10.0.0.1", "not an ip", 10]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	parseIP := func(str string) (interface{}, error) {
+		if ip := net.ParseIP(str) ; ip != nil {
+			return ip, nil
+		}
+
+		return nil, fmt.Errorf("invalid IP %q", str)
+	}
+
+	ip, ok := jp.Index(0).ParseWith(parseIP).(net.IP)
+	if !ok || !ip.Equal(net.IPv4(10, 0, 0, 1)) {
+		t.Errorf("Expected 10.0.0.1, got %#v", jp.Index(0).ParseWith(parseIP))
+	}
+
+	if val := jp.Index(1).ParseWith(parseIP) ; val != nil {
+		t.Errorf("Expected nil when the parser errors, got %#v", val)
+	}
+
+	if val := jp.Index(2).ParseWith(parseIP) ; val != nil {
+		t.Errorf("Expected nil for a non-string, got %#v", val)
+	}
+}
+
+func TestDebug(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`{"num": "05"}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	expected := `type=string kind=string Int64=5 Float64=5 Bool=false String="05"`
+
+	if debug := jp.Field("num").Debug() ; debug != expected {
+		t.Errorf("Expected %s, got %s", expected, debug)
+	}
+
+	expected = `type=<nil> kind=null Int64=0 Float64=0 Bool=false String=""`
+
+	if debug := jp.Field("missing").Debug() ; debug != expected {
+		t.Errorf("Expected %s, got %s", expected, debug)
+	}
+}
+
+func TestEntries(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`{"c": 3, "a": 1, "b": 2}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	keys, values := jp.Entries()
+
+	if fmt.Sprint(keys) != "[a b c]" {
+		t.Errorf("Expected sorted keys [a b c], got %v", keys)
+	}
+
+	if len(values) != len(keys) {
+		t.Fatalf("Expected %d values, got %d", len(keys), len(values))
+	}
+
+	for i, k := range keys {
+		if values[i].Int() != jp.Field(k).Int() {
+			t.Errorf("Expected value %d to belong to %s, got %v", i, k, values[i].I)
+		}
+	}
+
+	if keys, values := jp.Field("a").Entries() ; len(keys) != 0 || len(values) != 0 {
+		t.Errorf("Expected empty slices for a non-object, got %v %v", keys, values)
+	}
+}
+
+func TestExactCoercions(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`[42, "42", 2.5, "2.5x", 1e30, "99999999999999999999", {}]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if num, er := jp.Index(0).Int64Exact() ; er != nil || num != 42 {
+		t.Errorf("Expected 42, got %d (%v)", num, er)
+	}
+
+	if num, er := jp.Index(1).Int64Exact() ; er != nil || num != 42 {
+		t.Errorf("Expected 42, got %d (%v)", num, er)
+	}
+
+	if num, er := jp.Index(2).Float64Exact() ; er != nil || num != 2.5 {
+		t.Errorf("Expected 2.5, got %f (%v)", num, er)
+	}
+
+	expectedErrors := []struct {
+		index  int
+		int64  bool
+		reason string
+	}{
+		{2, true, "not an integer"},
+		{3, false, "not a number"},
+		{4, true, "out of range"},
+		{5, true, "out of range"},
+		{6, false, "not a number"},
+	}
+
+	for _, exp := range expectedErrors {
+		var er error
+
+		if exp.int64 {
+			_, er = jp.Index(exp.index).Int64Exact()
+		} else {
+			_, er = jp.Index(exp.index).Float64Exact()
+		}
+
+		if coercionEr, ok := er.(*CoercionError) ; !ok || coercionEr.Reason != exp.reason {
+			t.Errorf("Expected %q for %#v, got %#v", exp.reason, jp.Index(exp.index).I, er)
+		}
+	}
+
+	exactInts := []struct {
+		value    interface{}
+		expected int64
+		reason   string
+	}{
+		{json.Number("-9223372036854775809"), 0, "out of range"},
+		{json.Number("-9223372036854775808"), math.MinInt64, ""},
+		{"9223372036854775808.0", 0, "out of range"},
+		{"9007199254740993.0", 9007199254740993, ""},
+		{"9007199254740993.5", 0, "not an integer"},
+		{json.Number("1.5e3"), 1500, ""},
+	}
+
+	for _, exp := range exactInts {
+		num, er := (JPath{I: exp.value}).Int64Exact()
+
+		if exp.reason == "" {
+			if er != nil || num != exp.expected {
+				t.Errorf("Expected %d for %#v, got %d (%v)", exp.expected, exp.value, num, er)
+			}
+		} else if coercionEr, ok := er.(*CoercionError) ; !ok || coercionEr.Reason != exp.reason || num != 0 {
+			t.Errorf("Expected %q for %#v, got %d (%#v)", exp.reason, exp.value, num, er)
+		}
+	}
+
+	for _, v := range []interface{}{"NaN", "Inf", "-Infinity", math.NaN(), math.Inf(1), float32(math.Inf(-1))} {
+		num, er := (JPath{I: v}).Float64Exact()
+
+		if coercionEr, ok := er.(*CoercionError) ; !ok || coercionEr.Reason != "not finite" || num != 0 {
+			t.Errorf(`Expected "not finite" for %#v, got %v (%#v)`, v, num, er)
+		}
+
+		if _, er := (JPath{I: v}).Int64Exact() ; er == nil {
+			t.Errorf("Expected Int64Exact to reject %#v", v)
+		}
+	}
+}
+
+func TestMatches(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`{"email": "bob@example.com", "name": "bob", "tags": ["bob@example.com"]}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	const emailish = `[^@\s]+@[^@\s]+\.[a-z]+`
+
+	if !jp.Field("email").Matches(emailish) {
+		t.Errorf("Expected the email to match")
+	}
+
+	if jp.Field("name").Matches(emailish) {
+		t.Errorf("Did not expect the name to match")
+	}
+
+	if jp.Field("email").Matches(`bob`) {
+		t.Errorf("Expected a partial match to fail")
+	}
+
+	if jp.Field("tags").Matches(`.*`) {
+		t.Errorf("Did not expect an array to match")
+	}
+
+	if _, er := jp.Field("email").MatchesErr(`(`) ; er == nil {
+		t.Errorf("Expected an error for an invalid pattern")
+	}
+}
+
+func TestTypeCounts(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`{"ary": [1, 2, "x", null, [3], {"a": 1}, true], "obj": {"a": 1, "b": "s", "c": "t", "d": {"e": 2}}}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	expected := map[string]map[string]int{
+		"ary":   {"number": 2, "string": 1, "null": 1, "array": 1, "object": 1, "boolean": 1},
+		"obj":   {"number": 1, "string": 2, "object": 1},
+		"obj.b": {"string": 1},
+	}
+
+	for path, counts := range expected {
+		actual := jp.Get(path).TypeCounts()
+
+		if fmt.Sprint(actual) != fmt.Sprint(counts) {
+			t.Errorf("Expected %v for %s, got %v", counts, path, actual)
+		}
+	}
+}
+
+func TestFromStruct(t *testing.T) {
+	type address struct {
+		City string `json:"city"`
+	}
+
+	type user struct {
+		Name     string   `json:"name"`
+		Age      int      `json:"age"`
+		Password string   `json:"-"`
+		Address  address  `json:"address"`
+		Tags     []string `json:"tags"`
+	}
+
+	jp, er := FromStruct(user{"alice", 30, "hunter2", address{"Paris"}, []string{"admin"}})
+	if er != nil {
+		t.Fatal(er)
+	}
+
+	if name := jp.Field("name").String() ; name != "alice" {
+		t.Errorf("Expected alice, got %s", name)
+	}
+
+	if age := jp.Field("age").Int() ; age != 30 {
+		t.Errorf("Expected 30, got %d", age)
+	}
+
+	if city := jp.Get("address.city").String() ; city != "Paris" {
+		t.Errorf("Expected Paris, got %s", city)
+	}
+
+	if tag := jp.Get("tags.0").String() ; tag != "admin" {
+		t.Errorf("Expected admin, got %s", tag)
+	}
+
+	if jp.AnyKey("Password") {
+		t.Errorf("Expected json:\"-\" fields to be omitted")
+	}
+
+	if _, er := FromStruct(make(chan int)) ; er == nil {
+		t.Errorf("Expected an error for an unmarshalable value")
+	}
+}
+
+func TestParseBytesBOM(t *testing.T) {
+	documents := [][]byte{
+		append([]byte{0xEF, 0xBB, 0xBF}, `{"name": "bom"}`...),
+		[]byte("  \n\t{\"name\": \"bom\"}"),
+	}
+
+	for _, doc := range documents {
+		var jp JPath
+
+		if er := jp.ParseBytes(doc) ; er != nil {
+			t.Fatalf("Expected %q to parse, got %v", doc, er)
+		}
+
+		if name := jp.Field("name").String() ; name != "bom" {
+			t.Errorf("Expected bom, got %s", name)
+		}
+	}
+}
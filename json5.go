@@ -0,0 +1,455 @@
+package jpath
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// JSON5SyntaxError is returned by ParseJSON5 when its input is not valid JSON5.
+type JSON5SyntaxError struct {
+	// Offset is the byte offset into the input at which the error was detected.
+	Offset int
+	Msg    string
+}
+
+func (e *JSON5SyntaxError) Error() string {
+	return fmt.Sprintf("jpath: invalid JSON5 at offset %d: %s", e.Offset, e.Msg)
+}
+
+// ParseJSON5 parses the bytes as JSON5 and overwrites the underlying value with the result.
+// JSON5 extends JSON with comments, unquoted keys, single-quoted strings, trailing commas,
+// hexadecimal numbers, leading '+' signs, leading or trailing decimal points, and the numbers
+// Infinity and NaN. The result uses the same representation as ParseBytes, so all numbers
+// become float64.
+func (jp *JPath) ParseJSON5(bytes []byte) error {
+	p := json5Parser{src: string(bytes)}
+
+	val, er := p.parseDocument()
+	if er != nil {
+		return er
+	}
+
+	*jp = JPath{I: val}
+	return nil
+}
+
+type json5Parser struct {
+	src string
+	pos int
+}
+
+func (p *json5Parser) errorf(format string, args ...interface{}) error {
+	return &JSON5SyntaxError{p.pos, fmt.Sprintf(format, args...)}
+}
+
+func (p *json5Parser) parseDocument() (interface{}, error) {
+	val, er := p.parseValue()
+	if er != nil {
+		return nil, er
+	}
+
+	if er := p.skipSpace() ; er != nil {
+		return nil, er
+	}
+
+	if p.pos < len(p.src) {
+		return nil, p.errorf("unexpected trailing data")
+	}
+
+	return val, nil
+}
+
+// skipSpace advances past whitespace and comments.
+func (p *json5Parser) skipSpace() error {
+	for p.pos < len(p.src) {
+		r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+
+		switch {
+		case unicode.IsSpace(r) || r == '\uFEFF':
+			p.pos += size
+
+		case strings.HasPrefix(p.src[p.pos:], "//"):
+			end := strings.IndexAny(p.src[p.pos:], "\n\r\u2028\u2029")
+			if end < 0 {
+				p.pos = len(p.src)
+			} else {
+				p.pos += end
+			}
+
+		case strings.HasPrefix(p.src[p.pos:], "/*"):
+			end := strings.Index(p.src[p.pos + 2:], "*/")
+			if end < 0 {
+				return p.errorf("unterminated comment")
+			}
+
+			p.pos += end + 4
+
+		default:
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func (p *json5Parser) parseValue() (interface{}, error) {
+	if er := p.skipSpace() ; er != nil {
+		return nil, er
+	}
+
+	if p.pos >= len(p.src) {
+		return nil, p.errorf("unexpected end of input")
+	}
+
+	switch c := p.src[p.pos] ; {
+	case c == '{':
+		return p.parseObject()
+
+	case c == '[':
+		return p.parseArray()
+
+	case c == '"' || c == '\'':
+		return p.parseString()
+
+	case c == '-' || c == '+' || c == '.' || (c >= '0' && c <= '9'):
+		return p.parseNumber()
+	}
+
+	ident := p.parseIdentifier()
+
+	switch ident {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	case "Infinity":
+		return math.Inf(1), nil
+	case "NaN":
+		return math.NaN(), nil
+	}
+
+	return nil, p.errorf("unexpected %q", ident)
+}
+
+func (p *json5Parser) parseObject() (interface{}, error) {
+	obj := map[string]interface{}{}
+	p.pos += 1
+
+	for {
+		if er := p.skipSpace() ; er != nil {
+			return nil, er
+		}
+
+		if p.pos >= len(p.src) {
+			return nil, p.errorf("unterminated object")
+		}
+
+		if p.src[p.pos] == '}' {
+			p.pos += 1
+			return obj, nil
+		}
+
+		var key string
+
+		if c := p.src[p.pos] ; c == '"' || c == '\'' {
+			str, er := p.parseString()
+			if er != nil {
+				return nil, er
+			}
+
+			key = str.(string)
+
+		} else if key = p.parseIdentifier() ; key == "" {
+			return nil, p.errorf("expected an object key")
+		}
+
+		if er := p.skipSpace() ; er != nil {
+			return nil, er
+		}
+
+		if p.pos >= len(p.src) || p.src[p.pos] != ':' {
+			return nil, p.errorf("expected ':' after object key")
+		}
+
+		p.pos += 1
+
+		val, er := p.parseValue()
+		if er != nil {
+			return nil, er
+		}
+
+		obj[key] = val
+
+		if done, er := p.parseSeparator('}') ; er != nil {
+			return nil, er
+		} else if done {
+			return obj, nil
+		}
+	}
+}
+
+func (p *json5Parser) parseArray() (interface{}, error) {
+	ary := []interface{}{}
+	p.pos += 1
+
+	for {
+		if er := p.skipSpace() ; er != nil {
+			return nil, er
+		}
+
+		if p.pos >= len(p.src) {
+			return nil, p.errorf("unterminated array")
+		}
+
+		if p.src[p.pos] == ']' {
+			p.pos += 1
+			return ary, nil
+		}
+
+		val, er := p.parseValue()
+		if er != nil {
+			return nil, er
+		}
+
+		ary = append(ary, val)
+
+		if done, er := p.parseSeparator(']') ; er != nil {
+			return nil, er
+		} else if done {
+			return ary, nil
+		}
+	}
+}
+
+// parseSeparator consumes the ',' between members of an object or array, or the closing
+// delimiter. It reports whether the container was closed.
+func (p *json5Parser) parseSeparator(closing byte) (bool, error) {
+	if er := p.skipSpace() ; er != nil {
+		return false, er
+	}
+
+	if p.pos >= len(p.src) {
+		return false, p.errorf("expected ',' or %q", closing)
+	}
+
+	switch p.src[p.pos] {
+	case ',':
+		p.pos += 1
+		return false, nil
+
+	case closing:
+		p.pos += 1
+		return true, nil
+	}
+
+	return false, p.errorf("expected ',' or %q", closing)
+}
+
+// parseIdentifier consumes an ECMAScript-style identifier, returning "" if there isn't one.
+func (p *json5Parser) parseIdentifier() string {
+	start := p.pos
+
+	for p.pos < len(p.src) {
+		r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+
+		if r == '_' || r == '$' || unicode.IsLetter(r) || (p.pos > start && (unicode.IsDigit(r) || unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Pc, r))) {
+			p.pos += size
+		} else {
+			break
+		}
+	}
+
+	return p.src[start:p.pos]
+}
+
+func (p *json5Parser) parseString() (interface{}, error) {
+	quote := p.src[p.pos]
+	p.pos += 1
+
+	ret := strings.Builder{}
+
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+
+		switch {
+		case c == quote:
+			p.pos += 1
+			return ret.String(), nil
+
+		case c == '\n' || c == '\r':
+			return nil, p.errorf("unescaped line break in string")
+
+		case c == '\\':
+			if er := p.parseEscape(&ret) ; er != nil {
+				return nil, er
+			}
+
+		default:
+			ret.WriteByte(c)
+			p.pos += 1
+		}
+	}
+
+	return nil, p.errorf("unterminated string")
+}
+
+// parseEscape consumes a backslash escape sequence within a string, writing the character it
+// represents to out.
+func (p *json5Parser) parseEscape(out *strings.Builder) error {
+	p.pos += 1
+
+	if p.pos >= len(p.src) {
+		return p.errorf("unterminated string")
+	}
+
+	c := p.src[p.pos]
+	p.pos += 1
+
+	switch c {
+	case 'b':
+		out.WriteByte('\b')
+	case 'f':
+		out.WriteByte('\f')
+	case 'n':
+		out.WriteByte('\n')
+	case 'r':
+		out.WriteByte('\r')
+	case 't':
+		out.WriteByte('\t')
+	case 'v':
+		out.WriteByte('\v')
+	case '0':
+		out.WriteByte(0)
+
+	case '\r':
+		// Line continuation; swallow a following \n too.
+		if p.pos < len(p.src) && p.src[p.pos] == '\n' {
+			p.pos += 1
+		}
+
+	case '\n':
+		// Line continuation.
+
+	case 'x':
+		code, er := p.parseHex(2)
+		if er != nil {
+			return er
+		}
+
+		out.WriteRune(rune(code))
+
+	case 'u':
+		code, er := p.parseHex(4)
+		if er != nil {
+			return er
+		}
+
+		r := rune(code)
+
+		// A high surrogate pairs with a low surrogate in an immediately following \u escape. If
+		// there isn't one, the following escape is left to be read on its own.
+		if r >= 0xD800 && r < 0xDC00 && strings.HasPrefix(p.src[p.pos:], "\\u") && p.pos + 6 <= len(p.src) {
+			low, er := strconv.ParseUint(p.src[p.pos + 2:p.pos + 6], 16, 32)
+
+			if er == nil && low >= 0xDC00 && low < 0xE000 {
+				p.pos += 6
+				r = 0x10000 + (r - 0xD800) << 10 + (rune(low) - 0xDC00)
+			}
+		}
+
+		// Unpaired surrogates are written as U+FFFD, as encoding/json does.
+		out.WriteRune(r)
+
+	default:
+		// Any other escaped character stands for itself.
+		p.pos -= 1
+		r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+		out.WriteRune(r)
+		p.pos += size
+	}
+
+	return nil
+}
+
+func (p *json5Parser) parseHex(digits int) (uint64, error) {
+	if p.pos + digits > len(p.src) {
+		return 0, p.errorf("truncated hex escape")
+	}
+
+	code, er := strconv.ParseUint(p.src[p.pos:p.pos + digits], 16, 32)
+	if er != nil {
+		return 0, p.errorf("invalid hex escape %q", p.src[p.pos:p.pos + digits])
+	}
+
+	p.pos += digits
+	return code, nil
+}
+
+func (p *json5Parser) parseNumber() (interface{}, error) {
+	start := p.pos
+	sign := 1.0
+
+	if c := p.src[p.pos] ; c == '+' || c == '-' {
+		if c == '-' {
+			sign = -1
+		}
+
+		p.pos += 1
+	}
+
+	rest := p.src[p.pos:]
+
+	switch {
+	case strings.HasPrefix(rest, "Infinity"):
+		p.pos += len("Infinity")
+		return math.Inf(int(sign)), nil
+
+	case strings.HasPrefix(rest, "NaN"):
+		p.pos += len("NaN")
+		return math.NaN(), nil
+
+	case strings.HasPrefix(rest, "0x") || strings.HasPrefix(rest, "0X"):
+		p.pos += 2
+		digitsStart := p.pos
+
+		for p.pos < len(p.src) && strings.IndexByte("0123456789abcdefABCDEF", p.src[p.pos]) >= 0 {
+			p.pos += 1
+		}
+
+		num, er := strconv.ParseUint(p.src[digitsStart:p.pos], 16, 64)
+		if er != nil {
+			return nil, &JSON5SyntaxError{start, fmt.Sprintf("invalid hex number %q", p.src[start:p.pos])}
+		}
+
+		return sign * float64(num), nil
+	}
+
+	digitsStart := p.pos
+
+	for p.pos < len(p.src) && strings.IndexByte("0123456789.eE+-", p.src[p.pos]) >= 0 {
+		// A sign is only part of the number directly after an exponent marker.
+		if c := p.src[p.pos] ; (c == '+' || c == '-') && !(p.pos > digitsStart && (p.src[p.pos - 1] == 'e' || p.src[p.pos - 1] == 'E')) {
+			break
+		}
+
+		p.pos += 1
+	}
+
+	// As in JSON, a leading zero can't be followed by more digits.
+	if digits := p.src[digitsStart:p.pos] ; len(digits) > 1 && digits[0] == '0' && digits[1] >= '0' && digits[1] <= '9' {
+		return nil, &JSON5SyntaxError{start, fmt.Sprintf("invalid number %q: leading zero", p.src[start:p.pos])}
+	}
+
+	num, er := strconv.ParseFloat(p.src[digitsStart:p.pos], 64)
+	if er != nil {
+		return nil, &JSON5SyntaxError{start, fmt.Sprintf("invalid number %q", p.src[start:p.pos])}
+	}
+
+	return sign * num, nil
+}
@@ -0,0 +1,112 @@
+package jpath
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestParseJSON5(t *testing.T) {
+	var jp JPath
+	jsonBlob := []byte(`
+		// Service configuration.
+		{
+			name: 'widget',
+			"quoted": "still works",
+			mask: 0xFF,
+			offset: +1.5,
+			ratio: .25,
+			limit: Infinity,
+			escaped: 'it\'s here',
+			/* nested
+			   comment */
+			ports: [80, 443,],
+		}
+	`)
+
+	if er := jp.ParseJSON5(jsonBlob) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if name := jp.Field("name").String() ; name != "widget" {
+		t.Errorf("Expected widget, got %s", name)
+	}
+
+	if quoted := jp.Field("quoted").String() ; quoted != "still works" {
+		t.Errorf("Expected still works, got %s", quoted)
+	}
+
+	if mask := jp.Field("mask").Int() ; mask != 255 {
+		t.Errorf("Expected 255, got %d", mask)
+	}
+
+	if offset := jp.Field("offset").Float64() ; offset != 1.5 {
+		t.Errorf("Expected 1.5, got %f", offset)
+	}
+
+	if ratio := jp.Field("ratio").Float64() ; ratio != 0.25 {
+		t.Errorf("Expected 0.25, got %f", ratio)
+	}
+
+	if limit := jp.Field("limit").Float64() ; !math.IsInf(limit, 1) {
+		t.Errorf("Expected +Inf, got %f", limit)
+	}
+
+	if escaped := jp.Field("escaped").String() ; escaped != "it's here" {
+		t.Errorf("Expected it's here, got %s", escaped)
+	}
+
+	if ports := jp.Field("ports") ; ports.Length() != 2 || ports.Index(1).Int() != 443 {
+		t.Errorf("Expected [80, 443], got %#v", ports.I)
+	}
+
+	if er := jp.ParseJSON5([]byte(`[0, -0, 0.5, 0e3, 10]`)) ; er != nil {
+		t.Errorf("Expected zeros which aren't leading zeros to parse, got %v", er)
+	} else if ten := jp.Index(4).Int() ; ten != 10 {
+		t.Errorf("Expected 10, got %d", ten)
+	}
+}
+
+func TestParseJSON5Surrogates(t *testing.T) {
+	inputs := []string{
+		`"\uD83D\uDE00"`,
+		`"\uDC00\uDC00"`,
+		`"\uD83D\u0041"`,
+		`"\uD83D\uD83D\uDE00"`,
+		`"\uD83D"`,
+		`"\uD83Dx"`,
+	}
+
+	for _, input := range inputs {
+		var jp JPath
+		var expected string
+
+		if er := json.Unmarshal([]byte(input), &expected) ; er != nil {
+			t.Fatal(er)
+		}
+
+		if er := jp.ParseJSON5([]byte(input)) ; er != nil {
+			t.Errorf("Unexpected error for %s: %v", input, er)
+		} else if actual := jp.String() ; actual != expected {
+			t.Errorf("Expected %q for %s, as encoding/json decodes it, got %q", expected, input, actual)
+		}
+	}
+
+	var jp JPath
+
+	if er := jp.ParseJSON5([]byte(`"\uD83D\u00"`)) ; er == nil {
+		t.Errorf("Expected a truncated escape after a high surrogate to be an error")
+	}
+}
+
+func TestParseJSON5Invalid(t *testing.T) {
+	var jp JPath
+
+	for _, invalid := range []string{`{a: }`, `[1, 2`, `{'a': 1} x`, `'unterminated`, `{a: 01}`, `[-007]`, `00.5`} {
+		er := jp.ParseJSON5([]byte(invalid))
+
+		if _, ok := er.(*JSON5SyntaxError) ; !ok {
+			t.Errorf("Expected a *JSON5SyntaxError for %s, got %#v", invalid, er)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package jpath
+
+import (
+	"net"
+	"net/url"
+)
+
+// IP parses the underlying string as an IPv4 or IPv6 address. If the underlying value is not a
+// string, or is not a valid address, nil is returned.
+func (jp JPath) IP() net.IP {
+	if str, ok := jp.I.(string) ; ok {
+		return net.ParseIP(str)
+	}
+
+	return nil
+}
+
+// IPNet parses the underlying string as a CIDR network such as "192.0.2.0/24" or
+// "2001:db8::/32". If the underlying value is not a string, or is not valid CIDR notation, nil
+// is returned.
+func (jp JPath) IPNet() *net.IPNet {
+	if str, ok := jp.I.(string) ; ok {
+		if _, ipnet, er := net.ParseCIDR(str) ; er == nil {
+			return ipnet
+		}
+	}
+
+	return nil
+}
+
+// URL parses the underlying string with url.Parse, so both absolute and relative references are
+// accepted. If the underlying value is not a string, or can't be parsed, nil is returned.
+func (jp JPath) URL() *url.URL {
+	if str, ok := jp.I.(string) ; ok {
+		if u, er := url.Parse(str) ; er == nil {
+			return u
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package jpath
+
+import (
+	"net"
+	"testing"
+)
+
+func TestIP(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`["192.0.2.1", "2001:db8::1", "10.0.0.0/8", "not an ip", 42]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if actual := jp.Index(0).IP() ; !actual.Equal(net.IPv4(192, 0, 2, 1)) {
+		t.Errorf("Expected 192.0.2.1, got %v", actual)
+	}
+
+	if actual := jp.Index(1).IP() ; !actual.Equal(net.ParseIP("2001:db8::1")) || actual.To4() != nil {
+		t.Errorf("Expected 2001:db8::1, got %v", actual)
+	}
+
+	for i := 2 ; i < 5 ; i += 1 {
+		if actual := jp.Index(i).IP() ; actual != nil {
+			t.Errorf("Expected nil for element %d, got %v", i, actual)
+		}
+	}
+}
+
+func TestIPNet(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`["10.1.2.3/8", "2001:db8::/32", "10.0.0.1", "not a network", null]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if actual := jp.Index(0).IPNet() ; actual == nil || actual.String() != "10.0.0.0/8" {
+		t.Errorf("Expected 10.0.0.0/8, got %v", actual)
+	}
+
+	if actual := jp.Index(1).IPNet() ; actual == nil || actual.String() != "2001:db8::/32" {
+		t.Errorf("Expected 2001:db8::/32, got %v", actual)
+	}
+
+	for i := 2 ; i < 5 ; i += 1 {
+		if actual := jp.Index(i).IPNet() ; actual != nil {
+			t.Errorf("Expected nil for element %d, got %v", i, actual)
+		}
+	}
+}
+
+func TestURL(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`["https://example.com:8443/a/b?c=d", "../images/logo.png", "http://[::1", 42]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if actual := jp.Index(0).URL() ; actual == nil || actual.Scheme != "https" || actual.Host != "example.com:8443" || actual.Path != "/a/b" || actual.Query().Get("c") != "d" {
+		t.Errorf("Expected an absolute URL, got %v", actual)
+	}
+
+	if actual := jp.Index(1).URL() ; actual == nil || actual.IsAbs() || actual.Path != "../images/logo.png" {
+		t.Errorf("Expected a relative URL, got %v", actual)
+	}
+
+	for i := 2 ; i < 4 ; i += 1 {
+		if actual := jp.Index(i).URL() ; actual != nil {
+			t.Errorf("Expected nil for element %d, got %v", i, actual)
+		}
+	}
+}
@@ -0,0 +1,397 @@
+package jpath
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf16"
+)
+
+// ParseBytesNormalizeKeys parses the bytes as JSON like ParseBytes, then rewrites every object
+// key in the result through transform (e.g. strings.ToLower). If several keys of one object
+// transform to the same key, the one whose original key sorts last wins.
+func (jp *JPath) ParseBytesNormalizeKeys(bytes []byte, transform func(string) string) error {
+	if er := jp.ParseBytes(bytes) ; er != nil {
+		return er
+	}
+
+	jp.I = normalizeKeys(jp.I, transform)
+	return nil
+}
+
+func normalizeKeys(v interface{}, transform func(string) string) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(val))
+
+		for k := range val {
+			keys = append(keys, k)
+		}
+
+		sort.Strings(keys)
+		ret := make(map[string]interface{}, len(val))
+
+		for _, k := range keys {
+			ret[transform(k)] = normalizeKeys(val[k], transform)
+		}
+
+		return ret
+
+	case []interface{}:
+		for i, child := range val {
+			val[i] = normalizeKeys(child, transform)
+		}
+	}
+
+	return v
+}
+
+// GuardOptions bounds the size and shape of documents accepted by ParseGuarded. A limit of zero
+// (or less) disables that check.
+type GuardOptions struct {
+	// MaxDepth is the deepest nesting of objects and arrays allowed; a scalar root has depth 0.
+	MaxDepth int
+	// MaxNodes is the largest number of values (scalars, objects and arrays) allowed.
+	MaxNodes int
+	// MaxStringLen is the longest string, in bytes, allowed as either a key or a value.
+	MaxStringLen int
+	// MaxArrayLen is the largest number of elements allowed in any one array.
+	MaxArrayLen int
+}
+
+// GuardError is returned by ParseGuarded when the input exceeds one of its limits.
+type GuardError struct {
+	// Constraint is the name of the violated GuardOptions field, e.g. "MaxDepth".
+	Constraint string
+	Limit      int
+}
+
+func (e *GuardError) Error() string {
+	return fmt.Sprintf("jpath: document exceeds %s of %d", e.Constraint, e.Limit)
+}
+
+// guardFrame tracks an open object or array during ParseGuarded's scan.
+type guardFrame struct {
+	object    bool
+	count     int
+	expectKey bool
+}
+
+// ParseGuarded scans the bytes, rejecting them with a *GuardError if they exceed any of the
+// limits in opts, before parsing them like ParseBytes. The scan runs over the token stream, so
+// hostile input is rejected without building any of the tree. On error, the underlying value is
+// left untouched.
+func (jp *JPath) ParseGuarded(data []byte, opts GuardOptions) error {
+	dec := json.NewDecoder(bytes.NewReader(stripBOM(data)))
+	dec.UseNumber()
+
+	stack := []*guardFrame{}
+	nodes := 0
+	done := false
+
+	exceeds := func(n, limit int) bool {
+		return limit > 0 && n > limit
+	}
+
+	for {
+		tok, er := dec.Token()
+		if er == io.EOF {
+			break
+		} else if er != nil {
+			return er
+		}
+
+		if done {
+			return fmt.Errorf("jpath: unexpected data after top-level value")
+		}
+
+		if delim, ok := tok.(json.Delim) ; ok && (delim == '}' || delim == ']') {
+			stack = stack[:len(stack) - 1]
+			done = len(stack) == 0
+			continue
+		}
+
+		var top *guardFrame
+		if len(stack) > 0 {
+			top = stack[len(stack) - 1]
+		}
+
+		if str, ok := tok.(string) ; ok && exceeds(len(str), opts.MaxStringLen) {
+			return &GuardError{"MaxStringLen", opts.MaxStringLen}
+		}
+
+		if top != nil && top.object && top.expectKey {
+			top.expectKey = false
+			continue
+		}
+
+		if top != nil {
+			if top.object {
+				top.expectKey = true
+			} else if top.count += 1 ; exceeds(top.count, opts.MaxArrayLen) {
+				return &GuardError{"MaxArrayLen", opts.MaxArrayLen}
+			}
+		}
+
+		if nodes += 1 ; exceeds(nodes, opts.MaxNodes) {
+			return &GuardError{"MaxNodes", opts.MaxNodes}
+		}
+
+		if delim, ok := tok.(json.Delim) ; ok {
+			stack = append(stack, &guardFrame{object: delim == '{', expectKey: delim == '{'})
+
+			if exceeds(len(stack), opts.MaxDepth) {
+				return &GuardError{"MaxDepth", opts.MaxDepth}
+			}
+		} else if len(stack) == 0 {
+			done = true
+		}
+	}
+
+	// Unmarshal into a local, so a failure here leaves the receiver untouched too.
+	var val interface{}
+	if er := json.Unmarshal(stripBOM(data), &val) ; er != nil {
+		return er
+	}
+
+	*jp = JPath{I: val}
+	return nil
+}
+
+// ParseBytesMultiKey parses the bytes as JSON like ParseBytes, except that when an object
+// repeats a key, every value given for it is kept: the key maps to an array of the values, in
+// the order they appeared. Keys which appear once are unaffected.
+func (jp *JPath) ParseBytesMultiKey(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(stripBOM(data)))
+
+	val, er := decodeMultiKey(dec)
+	if er != nil {
+		return er
+	}
+
+	if _, er := dec.Token() ; er != io.EOF {
+		return fmt.Errorf("jpath: unexpected data after top-level value")
+	}
+
+	*jp = JPath{I: val}
+	return nil
+}
+
+func decodeMultiKey(dec *json.Decoder) (interface{}, error) {
+	tok, er := dec.Token()
+	if er != nil {
+		return nil, er
+	}
+
+	switch tok {
+	case json.Delim('{'):
+		obj := map[string]interface{}{}
+		repeated := map[string]bool{}
+
+		for dec.More() {
+			keyTok, er := dec.Token()
+			if er != nil {
+				return nil, er
+			}
+
+			key := keyTok.(string)
+
+			val, er := decodeMultiKey(dec)
+			if er != nil {
+				return nil, er
+			}
+
+			if prev, ok := obj[key] ; !ok {
+				obj[key] = val
+			} else if repeated[key] {
+				obj[key] = append(prev.([]interface{}), val)
+			} else {
+				repeated[key] = true
+				obj[key] = []interface{}{prev, val}
+			}
+		}
+
+		_, er = dec.Token()
+		return obj, er
+
+	case json.Delim('['):
+		ary := []interface{}{}
+
+		for dec.More() {
+			val, er := decodeMultiKey(dec)
+			if er != nil {
+				return nil, er
+			}
+
+			ary = append(ary, val)
+		}
+
+		_, er = dec.Token()
+		return ary, er
+	}
+
+	return tok, nil
+}
+
+// ParseBytesTrim parses the bytes as JSON like ParseBytes, then trims leading and trailing
+// whitespace from every string in the result. Strings which are entirely whitespace become empty
+// strings. Object keys are not trimmed.
+func (jp *JPath) ParseBytesTrim(data []byte) error {
+	if er := jp.ParseBytes(data) ; er != nil {
+		return er
+	}
+
+	jp.I = trimStrings(jp.I)
+	return nil
+}
+
+func trimStrings(v interface{}) interface{} {
+	switch val := v.(type) {
+	case string:
+		return strings.TrimSpace(val)
+
+	case map[string]interface{}:
+		for k, child := range val {
+			val[k] = trimStrings(child)
+		}
+
+	case []interface{}:
+		for i, child := range val {
+			val[i] = trimStrings(child)
+		}
+	}
+
+	return v
+}
+
+// ParseBytesEncoding transcodes the bytes from the named encoding to UTF-8, then parses them as
+// JSON like ParseBytes. The supported encodings, matched case-insensitively, are:
+//
+//     "utf-8"                       no transcoding
+//     "utf-16le", "utf-16be"        UTF-16 of the given byte order
+//     "utf-16"                      UTF-16 whose byte order is given by a leading byte order
+//                                   mark, or big-endian if there isn't one
+//     "latin-1", "iso-8859-1"       ISO 8859-1
+//
+// A leading byte order mark is discarded. An error is returned for any other encoding, and for
+// UTF-16 input of odd length.
+func (jp *JPath) ParseBytesEncoding(data []byte, enc string) error {
+	decoded, er := decodeEncoding(data, enc)
+	if er != nil {
+		return er
+	}
+
+	return jp.ParseBytes(decoded)
+}
+
+func decodeEncoding(data []byte, enc string) ([]byte, error) {
+	switch strings.ToLower(enc) {
+	case "utf-8", "utf8":
+		return data, nil
+
+	case "latin-1", "latin1", "iso-8859-1":
+		ret := bytes.Buffer{}
+
+		// Latin-1 code points are exactly the first 256 of Unicode.
+		for _, b := range data {
+			ret.WriteRune(rune(b))
+		}
+
+		return ret.Bytes(), nil
+
+	case "utf-16":
+		if bytes.HasPrefix(data, []byte{0xFF, 0xFE}) {
+			return decodeUTF16(data, binary.LittleEndian)
+		}
+
+		return decodeUTF16(data, binary.BigEndian)
+
+	case "utf-16le":
+		return decodeUTF16(data, binary.LittleEndian)
+
+	case "utf-16be":
+		return decodeUTF16(data, binary.BigEndian)
+	}
+
+	return nil, fmt.Errorf("jpath: unsupported encoding %q", enc)
+}
+
+// decodeUTF16 transcodes UTF-16 data of the given byte order to UTF-8. A byte order mark is
+// carried through, and is later discarded by ParseBytes.
+func decodeUTF16(data []byte, order binary.ByteOrder) ([]byte, error) {
+	if len(data) % 2 != 0 {
+		return nil, fmt.Errorf("jpath: UTF-16 input has odd length %d", len(data))
+	}
+
+	units := make([]uint16, len(data) / 2)
+
+	for i := range units {
+		units[i] = order.Uint16(data[i * 2:])
+	}
+
+	ret := bytes.Buffer{}
+
+	for _, r := range utf16.Decode(units) {
+		ret.WriteRune(r)
+	}
+
+	return ret.Bytes(), nil
+}
+
+// TrailingDataError is returned by ParseReaderSingle when anything other than whitespace follows
+// the first JSON value read.
+type TrailingDataError struct {
+	// Offset is the byte offset into the input of the first unexpected byte.
+	Offset int64
+}
+
+func (e *TrailingDataError) Error() string {
+	return fmt.Sprintf("jpath: unexpected data after top-level value at offset %d", e.Offset)
+}
+
+// ParseReaderSingle decodes one JSON value from r, as ParseBytes would, and overwrites the
+// underlying value with it. Unlike ParseReader, the input isn't buffered in full before decoding.
+// Once the value has been decoded, the rest of r is read; if anything other than whitespace
+// remains, a *TrailingDataError is returned and the receiver is left untouched.
+func (jp *JPath) ParseReaderSingle(r io.Reader) error {
+	br := bufio.NewReader(r)
+	offset := int64(0)
+
+	if prefix, _ := br.Peek(len(utf8BOM)) ; bytes.Equal(prefix, utf8BOM) {
+		br.Discard(len(utf8BOM))
+		offset = int64(len(utf8BOM))
+	}
+
+	dec := json.NewDecoder(br)
+
+	var val interface{}
+	if er := dec.Decode(&val) ; er != nil {
+		return er
+	}
+
+	offset += dec.InputOffset()
+	rest := bufio.NewReader(io.MultiReader(dec.Buffered(), br))
+
+	for {
+		c, er := rest.ReadByte()
+		if er == io.EOF {
+			break
+		} else if er != nil {
+			return er
+		}
+
+		if c != ' ' && c != '\t' && c != '\n' && c != '\r' {
+			return &TrailingDataError{offset}
+		}
+
+		offset += 1
+	}
+
+	*jp = JPath{I: val}
+	return nil
+}
@@ -0,0 +1,205 @@
+package jpath
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+func TestParseBytesNormalizeKeys(t *testing.T) {
+	var jp JPath
+	jsonBlob := []byte(`{
+		"UserName": "alice",
+		"Address": {"City": "Paris", "ZIP": "75001"},
+		"Tags": [{"Name": "a"}],
+		"ID": 1,
+		"id": 2
+	}`)
+
+	if er := jp.ParseBytesNormalizeKeys(jsonBlob, strings.ToLower) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if name := jp.Field("username").String() ; name != "alice" {
+		t.Errorf("Expected alice, got %s", name)
+	}
+
+	if zip := jp.Get("address.zip").String() ; zip != "75001" {
+		t.Errorf("Expected 75001, got %s", zip)
+	}
+
+	if tag := jp.Get("tags.0.name").String() ; tag != "a" {
+		t.Errorf("Expected a, got %s", tag)
+	}
+
+	// "id" sorts after "ID", so it wins the collision.
+	if id := jp.Field("id").Int() ; id != 2 {
+		t.Errorf("Expected 2, got %d", id)
+	}
+
+	if len(jp.Fields()) != 4 {
+		t.Errorf("Expected 4 fields, got %v", jp.Fields())
+	}
+}
+
+func TestParseGuarded(t *testing.T) {
+	jsonBlob := []byte(`{"name": "widget", "tags": ["a", "b", "c"], "meta": {"owner": {"id": 1}}}`)
+	opts := GuardOptions{MaxDepth: 3, MaxNodes: 9, MaxStringLen: 6, MaxArrayLen: 3}
+
+	var jp JPath
+
+	if er := jp.ParseGuarded(jsonBlob, opts) ; er != nil {
+		t.Fatalf("Expected the document to be within limits, got %v", er)
+	}
+
+	if name := jp.Field("name").String() ; name != "widget" {
+		t.Errorf("Expected widget, got %s", name)
+	}
+
+	violations := map[string]GuardOptions{
+		"MaxDepth":     {MaxDepth: 2},
+		"MaxNodes":     {MaxNodes: 8},
+		"MaxStringLen": {MaxStringLen: 5},
+		"MaxArrayLen":  {MaxArrayLen: 2},
+	}
+
+	for constraint, opts := range violations {
+		er := jp.ParseGuarded(jsonBlob, opts)
+
+		if guardEr, ok := er.(*GuardError) ; !ok || guardEr.Constraint != constraint {
+			t.Errorf("Expected a %s *GuardError, got %#v", constraint, er)
+		}
+	}
+
+	for _, input := range []string{`{} {}`, `1 2`, `[1] "x"`, `{"a": 1`, ``} {
+		keep := JPath{I: "keep"}
+
+		if er := keep.ParseGuarded([]byte(input), GuardOptions{}) ; er == nil {
+			t.Errorf("Expected an error for %q", input)
+		}
+
+		if keep.I != "keep" {
+			t.Errorf("Expected the receiver to be untouched after %q failed, got %#v", input, keep.I)
+		}
+	}
+}
+
+func TestParseBytesMultiKey(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseBytesMultiKey([]byte(`{"tag": "a", "name": "x", "tag": "b", "tag": ["c"], "nested": {"one": 1}}`)) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if len(jp.Fields()) != 3 {
+		t.Errorf("Expected 3 fields, got %v", jp.Fields())
+	}
+
+	tags := jp.Field("tag")
+
+	if tags.Length() != 3 || tags.Index(0).String() != "a" || tags.Index(1).String() != "b" || tags.Index(2).Index(0).String() != "c" {
+		t.Errorf(`Expected ["a", "b", ["c"]], got %#v`, tags.I)
+	}
+
+	if name := jp.Field("name").String() ; name != "x" {
+		t.Errorf("Expected x, got %s", name)
+	}
+
+	if one := jp.Get("nested.one").Int() ; one != 1 {
+		t.Errorf("Expected 1, got %d", one)
+	}
+
+	if er := jp.ParseBytesMultiKey([]byte(`{"a": 1} {}`)) ; er == nil {
+		t.Errorf("Expected an error for trailing data")
+	}
+}
+
+func TestParseBytesTrim(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseBytesTrim([]byte(`{"name": "  hello  ", "tags": ["\ta\n", "   "], "count": 3, "ok": true}`)) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if name := jp.Field("name").String() ; name != "hello" {
+		t.Errorf("Expected hello, got %q", name)
+	}
+
+	if tag := jp.Get("tags.0").String() ; tag != "a" {
+		t.Errorf("Expected a, got %q", tag)
+	}
+
+	if blank, ok := jp.Get("tags.1").I.(string) ; !ok || blank != "" {
+		t.Errorf("Expected an empty string, got %#v", jp.Get("tags.1").I)
+	}
+
+	if jp.Field("count").I != 3.0 || jp.Field("ok").I != true {
+		t.Errorf("Expected non-string values to be untouched, got %#v", jp.I)
+	}
+}
+
+func TestParseBytesEncoding(t *testing.T) {
+	var jp JPath
+
+	// {"name": "Zoë 😀"} in UTF-16LE, with a byte order mark.
+	utf16le := []byte{0xFF, 0xFE}
+
+	for _, r := range utf16.Encode([]rune(`{"name": "Zoë 😀"}`)) {
+		utf16le = append(utf16le, byte(r), byte(r >> 8))
+	}
+
+	for _, enc := range []string{"UTF-16LE", "utf-16"} {
+		if er := jp.ParseBytesEncoding(utf16le, enc) ; er != nil {
+			t.Fatal(er)
+		}
+
+		if name := jp.Field("name").String() ; name != "Zoë 😀" {
+			t.Errorf("Expected Zoë 😀 from %s, got %q", enc, name)
+		}
+	}
+
+	// {"city": "Malmö"} in Latin-1, where ö is the single byte 0xF6.
+	if er := jp.ParseBytesEncoding([]byte("{\"city\": \"Malm\xF6\"}"), "iso-8859-1") ; er != nil {
+		t.Fatal(er)
+	}
+
+	if city := jp.Field("city").String() ; city != "Malmö" {
+		t.Errorf("Expected Malmö, got %q", city)
+	}
+
+	if er := jp.ParseBytesEncoding([]byte(`{}`), "ebcdic") ; er == nil {
+		t.Errorf("Expected an error for an unsupported encoding")
+	}
+
+	if er := jp.ParseBytesEncoding(utf16le[:len(utf16le) - 1], "utf-16le") ; er == nil {
+		t.Errorf("Expected an error for odd-length UTF-16")
+	}
+}
+
+func TestParseReaderSingle(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseReaderSingle(strings.NewReader("\xEF\xBB\xBF {\"id\": 7, \"tags\": [\"a\"]} \n\t")) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if id := jp.Field("id").Int() ; id != 7 {
+		t.Errorf("Expected 7, got %d", id)
+	}
+
+	er := jp.ParseReaderSingle(strings.NewReader(`{"id": 8}  {"id": 9}`))
+
+	if trailing, ok := er.(*TrailingDataError) ; !ok || trailing.Offset != 11 {
+		t.Errorf("Expected a TrailingDataError at offset 11, got %v", er)
+	}
+
+	if id := jp.Field("id").Int() ; id != 7 {
+		t.Errorf("Expected the receiver to be untouched after an error, got %d", id)
+	}
+
+	if er := jp.ParseReaderSingle(strings.NewReader(`[1, 2`)) ; er == nil {
+		t.Errorf("Expected an error for malformed input")
+	} else if _, ok := er.(*TrailingDataError) ; ok {
+		t.Errorf("Expected a syntax error rather than a TrailingDataError, got %v", er)
+	}
+}
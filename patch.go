@@ -0,0 +1,343 @@
+package jpath
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// PatchError is returned by ApplyPatch when an operation can't be applied.
+type PatchError struct {
+	// Index is the position of the failed operation within the patch.
+	Index int
+	Op    string
+	Path  string
+	Msg   string
+}
+
+func (e *PatchError) Error() string {
+	return fmt.Sprintf("jpath: patch operation %d (%s %q): %s", e.Index, e.Op, e.Path, e.Msg)
+}
+
+// MakePatch returns a JPath wrapping an RFC 6902 JSON Patch -- an array of "add", "remove" and
+// "replace" operations -- which transforms the underlying value into target when passed to
+// ApplyPatch. Objects are compared field by field and arrays element by element; values which
+// differ in type are replaced outright. Numbers are compared by value.
+func (jp JPath) MakePatch(target JPath) JPath {
+	return JPath{I: makePatch([]interface{}{}, []string{}, jp.I, target.I)}
+}
+
+func patchOp(op string, tokens []string, value ...interface{}) map[string]interface{} {
+	ret := map[string]interface{}{"op": op, "path": joinPointer(tokens)}
+
+	if len(value) > 0 {
+		ret["value"] = deepCopy(value[0])
+	}
+
+	return ret
+}
+
+func makePatch(ops []interface{}, tokens []string, src, dst interface{}) []interface{} {
+	child := func(tok string) []string {
+		return append(tokens[:len(tokens):len(tokens)], tok)
+	}
+
+	srcObj, srcIsObj := src.(map[string]interface{})
+	dstObj, dstIsObj := dst.(map[string]interface{})
+
+	if srcIsObj && dstIsObj {
+		keys := (JPath{I: srcObj}).SortedFields()
+
+		for _, k := range keys {
+			if _, ok := dstObj[k] ; !ok {
+				ops = append(ops, patchOp("remove", child(k)))
+			} else {
+				ops = makePatch(ops, child(k), srcObj[k], dstObj[k])
+			}
+		}
+
+		keys = (JPath{I: dstObj}).SortedFields()
+
+		for _, k := range keys {
+			if _, ok := srcObj[k] ; !ok {
+				ops = append(ops, patchOp("add", child(k), dstObj[k]))
+			}
+		}
+
+		return ops
+	}
+
+	srcAry, srcIsAry := src.([]interface{})
+	dstAry, dstIsAry := dst.([]interface{})
+
+	if srcIsAry && dstIsAry {
+		common := len(srcAry)
+		if len(dstAry) < common {
+			common = len(dstAry)
+		}
+
+		for i := 0 ; i < common ; i += 1 {
+			ops = makePatch(ops, child(strconv.Itoa(i)), srcAry[i], dstAry[i])
+		}
+
+		// Remove from the end, so earlier removals don't shift later indices.
+		for i := len(srcAry) - 1 ; i >= common ; i -= 1 {
+			ops = append(ops, patchOp("remove", child(strconv.Itoa(i))))
+		}
+
+		for i := common ; i < len(dstAry) ; i += 1 {
+			ops = append(ops, patchOp("add", child(strconv.Itoa(i)), dstAry[i]))
+		}
+
+		return ops
+	}
+
+	if !jsonEqual(src, dst) {
+		ops = append(ops, patchOp("replace", tokens, dst))
+	}
+
+	return ops
+}
+
+// ApplyPatch applies the RFC 6902 JSON Patch wrapped by patch to a deep copy of the underlying
+// value, and returns a JPath wrapping the result. All six operations ("add", "remove",
+// "replace", "move", "copy" and "test") are supported. If any operation fails, a *PatchError
+// is returned and none of the patch is applied. The receiver is never modified.
+func (jp JPath) ApplyPatch(patch JPath) (JPath, error) {
+	if _, ok := patch.I.([]interface{}) ; !ok {
+		return JPath{}, &PatchError{-1, "", "", "patch is not an array"}
+	}
+
+	doc := deepCopy(jp.I)
+
+	for i := 0 ; i < patch.Length() ; i += 1 {
+		op := patch.Index(i)
+		name, path := op.Field("op").String(), op.Field("path").String()
+
+		var er error
+		if doc, er = applyPatchOp(doc, op) ; er != nil {
+			return JPath{}, &PatchError{i, name, path, er.Error()}
+		}
+	}
+
+	return JPath{I: doc}, nil
+}
+
+func applyPatchOp(doc interface{}, op JPath) (interface{}, error) {
+	tokens, er := splitPointer(op.Field("path").String())
+	if er != nil {
+		return doc, er
+	}
+
+	obj, _ := op.AsMap()
+	value, hasValue := obj["value"]
+
+	switch name := op.Field("op").String() ; name {
+	case "add":
+		if !hasValue {
+			return doc, fmt.Errorf("missing value")
+		}
+
+		return pointerAdd(doc, tokens, deepCopy(value))
+
+	case "remove":
+		doc, _, er = pointerRemove(doc, tokens)
+		return doc, er
+
+	case "replace":
+		if !hasValue {
+			return doc, fmt.Errorf("missing value")
+		}
+
+		if doc, _, er = pointerRemove(doc, tokens) ; er != nil {
+			return doc, er
+		}
+
+		return pointerAdd(doc, tokens, deepCopy(value))
+
+	case "move", "copy":
+		from, er := splitPointer(op.Field("from").String())
+		if er != nil {
+			return doc, er
+		}
+
+		moved, er := pointerGet(doc, from)
+		if er != nil {
+			return doc, er
+		}
+
+		if name == "move" {
+			if doc, _, er = pointerRemove(doc, from) ; er != nil {
+				return doc, er
+			}
+		} else {
+			moved = deepCopy(moved)
+		}
+
+		return pointerAdd(doc, tokens, moved)
+
+	case "test":
+		actual, er := pointerGet(doc, tokens)
+		if er != nil {
+			return doc, er
+		}
+
+		if !jsonEqual(actual, value) {
+			return doc, fmt.Errorf("test failed")
+		}
+
+		return doc, nil
+
+	default:
+		return doc, fmt.Errorf("unknown operation %q", name)
+	}
+}
+
+// arrayIndex parses tok as an index into ary. If allowEnd is set, "-" and len(ary) are
+// accepted as referring to the position after the last element.
+func arrayIndex(ary []interface{}, tok string, allowEnd bool) (int, error) {
+	if tok == "-" && allowEnd {
+		return len(ary), nil
+	}
+
+	i, er := strconv.Atoi(tok)
+	if er != nil || i < 0 || i > len(ary) || (i == len(ary) && !allowEnd) || strconv.Itoa(i) != tok {
+		return 0, fmt.Errorf("invalid array index %q", tok)
+	}
+
+	return i, nil
+}
+
+// pointerGet returns the value at the location tokens refers to within doc.
+func pointerGet(doc interface{}, tokens []string) (interface{}, error) {
+	cur := doc
+
+	for _, tok := range tokens {
+		switch val := cur.(type) {
+		case map[string]interface{}:
+			child, ok := val[tok]
+			if !ok {
+				return nil, fmt.Errorf("no member %q", tok)
+			}
+
+			cur = child
+
+		case []interface{}:
+			i, er := arrayIndex(val, tok, false)
+			if er != nil {
+				return nil, er
+			}
+
+			cur = val[i]
+
+		default:
+			return nil, fmt.Errorf("cannot index into %s", jsonKind(cur))
+		}
+	}
+
+	return cur, nil
+}
+
+// pointerUpdate calls fn with the container holding the location tokens refers to and the final
+// token, replacing that container with fn's result. It returns the updated doc.
+func pointerUpdate(doc interface{}, tokens []string, fn func(parent interface{}, tok string) (interface{}, error)) (interface{}, error) {
+	if len(tokens) == 1 {
+		return fn(doc, tokens[0])
+	}
+
+	switch val := doc.(type) {
+	case map[string]interface{}:
+		child, ok := val[tokens[0]]
+		if !ok {
+			return doc, fmt.Errorf("no member %q", tokens[0])
+		}
+
+		child, er := pointerUpdate(child, tokens[1:], fn)
+		if er != nil {
+			return doc, er
+		}
+
+		val[tokens[0]] = child
+		return val, nil
+
+	case []interface{}:
+		i, er := arrayIndex(val, tokens[0], false)
+		if er != nil {
+			return doc, er
+		}
+
+		child, er := pointerUpdate(val[i], tokens[1:], fn)
+		if er != nil {
+			return doc, er
+		}
+
+		val[i] = child
+		return val, nil
+	}
+
+	return doc, fmt.Errorf("cannot index into %s", jsonKind(doc))
+}
+
+// pointerAdd performs the JSON Patch "add" operation, returning the updated doc.
+func pointerAdd(doc interface{}, tokens []string, value interface{}) (interface{}, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+
+	return pointerUpdate(doc, tokens, func(parent interface{}, tok string) (interface{}, error) {
+		switch val := parent.(type) {
+		case map[string]interface{}:
+			val[tok] = value
+			return val, nil
+
+		case []interface{}:
+			i, er := arrayIndex(val, tok, true)
+			if er != nil {
+				return parent, er
+			}
+
+			val = append(val, nil)
+			copy(val[i + 1:], val[i:])
+			val[i] = value
+			return val, nil
+		}
+
+		return parent, fmt.Errorf("cannot add to %s", jsonKind(parent))
+	})
+}
+
+// pointerRemove performs the JSON Patch "remove" operation, returning the updated doc along with
+// the removed value.
+func pointerRemove(doc interface{}, tokens []string) (interface{}, interface{}, error) {
+	if len(tokens) == 0 {
+		return nil, doc, nil
+	}
+
+	var removed interface{}
+
+	doc, er := pointerUpdate(doc, tokens, func(parent interface{}, tok string) (interface{}, error) {
+		switch val := parent.(type) {
+		case map[string]interface{}:
+			child, ok := val[tok]
+			if !ok {
+				return parent, fmt.Errorf("no member %q", tok)
+			}
+
+			removed = child
+			delete(val, tok)
+			return val, nil
+
+		case []interface{}:
+			i, er := arrayIndex(val, tok, false)
+			if er != nil {
+				return parent, er
+			}
+
+			removed = val[i]
+			return append(val[:i:i], val[i + 1:]...), nil
+		}
+
+		return parent, fmt.Errorf("cannot remove from %s", jsonKind(parent))
+	})
+
+	return doc, removed, er
+}
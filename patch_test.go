@@ -0,0 +1,86 @@
+package jpath
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMakePatch(t *testing.T) {
+	var src, dst JPath
+
+	if er := src.ParseString(`{"name": "widget", "tags": ["a", "b", "c"], "meta": {"old": true, "v": 1}, "a/b": 1}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if er := dst.ParseString(`{"name": "gadget", "tags": ["a", "x"], "meta": {"v": 2, "new": [1]}, "a/b": 1, "extra": null}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	patch := src.MakePatch(dst)
+
+	for i := 0 ; i < patch.Length() ; i += 1 {
+		if op := patch.Index(i).Field("op").String() ; op != "add" && op != "remove" && op != "replace" {
+			t.Errorf("Unexpected operation %s", op)
+		}
+	}
+
+	patched, er := src.ApplyPatch(patch)
+	if er != nil {
+		t.Fatal(er)
+	}
+
+	if !reflect.DeepEqual(patched.I, dst.I) {
+		t.Errorf("Expected the patch to reproduce the target, got %#v", patched.I)
+	}
+
+	if src.Field("name").String() != "widget" {
+		t.Errorf("Original value was modified")
+	}
+
+	if empty := src.MakePatch(src) ; empty.Length() != 0 {
+		t.Errorf("Expected no operations between identical documents, got %#v", empty.I)
+	}
+}
+
+func TestApplyPatch(t *testing.T) {
+	var doc, patch JPath
+
+	if er := doc.ParseString(`{"list": [1, 2], "a": {"b": "c"}}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if er := patch.ParseString(`[
+		{"op": "test", "path": "/a/b", "value": "c"},
+		{"op": "add", "path": "/list/-", "value": 3},
+		{"op": "add", "path": "/list/0", "value": 0},
+		{"op": "copy", "from": "/a", "path": "/copied"},
+		{"op": "move", "from": "/a/b", "path": "/moved"},
+		{"op": "remove", "path": "/list/1"}
+	]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	patched, er := doc.ApplyPatch(patch)
+	if er != nil {
+		t.Fatal(er)
+	}
+
+	var expected JPath
+
+	if er := expected.ParseString(`{"list": [0, 2, 3], "a": {}, "copied": {"b": "c"}, "moved": "c"}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if !reflect.DeepEqual(patched.I, expected.I) {
+		t.Errorf("Unexpected result %#v", patched.I)
+	}
+
+	if er := patch.ParseString(`[{"op": "add", "path": "/x", "value": 1}, {"op": "test", "path": "/x", "value": 2}]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	_, er = doc.ApplyPatch(patch)
+	if patchEr, ok := er.(*PatchError) ; !ok || patchEr.Index != 1 {
+		t.Errorf("Expected a *PatchError for the failed test, got %#v", er)
+	}
+}
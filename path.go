@@ -0,0 +1,417 @@
+package jpath
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// splitPath splits a path into its segments. Both '.' and '/' separate segments, and a
+// backslash causes the following character to be taken literally, so "a\.b" is the single
+// segment "a.b". Empty segments, such as those produced by leading or trailing separators, are
+// dropped.
+func splitPath(path string) []string {
+	segments := []string{}
+	cur := strings.Builder{}
+	escaped := false
+
+	flush := func() {
+		if cur.Len() > 0 {
+			segments = append(segments, cur.String())
+			cur.Reset()
+		}
+	}
+
+	for _, r := range path {
+		switch {
+		case escaped:
+			cur.WriteRune(r)
+			escaped = false
+
+		case r == '\\':
+			escaped = true
+
+		case r == '.' || r == '/':
+			flush()
+
+		default:
+			cur.WriteRune(r)
+		}
+	}
+
+	flush()
+	return segments
+}
+
+// escapeSegment backslash-escapes any separators or backslashes in seg, so that it survives
+// splitPath as a single segment.
+func escapeSegment(seg string) string {
+	if !strings.ContainsAny(seg, `./\`) {
+		return seg
+	}
+
+	ret := strings.Builder{}
+
+	for _, r := range seg {
+		if r == '.' || r == '/' || r == '\\' {
+			ret.WriteRune('\\')
+		}
+
+		ret.WriteRune(r)
+	}
+
+	return ret.String()
+}
+
+// joinPath is the inverse of splitPath, joining segments with '.'.
+func joinPath(segments []string) string {
+	escaped := make([]string, len(segments))
+
+	for i, seg := range segments {
+		escaped[i] = escapeSegment(seg)
+	}
+
+	return strings.Join(escaped, ".")
+}
+
+// child returns the JPath reached by applying a single path segment: a field name for objects
+// or an index for arrays.
+func (jp JPath) child(seg string) JPath {
+	if _, ok := jp.I.([]interface{}) ; ok {
+		i, er := strconv.Atoi(seg)
+
+		if er != nil || i < 0 {
+			return JPath{}
+		}
+
+		return jp.Index(i)
+	}
+
+	return jp.Field(seg)
+}
+
+// Get resolves a path such as "users.2.name" against the underlying value. Segments are
+// separated by '.' or '/' (so "users/2/name" is equivalent), and leading or trailing separators
+// are ignored. Numeric segments index into arrays, everything else names an object field. A
+// separator can be used literally within a segment by escaping it with a backslash, as in
+// "version\.major". If any segment can't be resolved, a zero-value JPath is returned.
+func (jp JPath) Get(path string) JPath {
+	cur := jp
+
+	for _, seg := range splitPath(path) {
+		if cur.I == nil {
+			return JPath{}
+		}
+
+		cur = cur.child(seg)
+	}
+
+	return cur
+}
+
+// GetNumber resolves path like Get and returns the value found there coerced with Float64. The
+// returned bool is true only if the value is a genuine JSON number; it is false for values
+// coerced from strings such as "3.5", and for missing paths, which yield 0.
+func (jp JPath) GetNumber(path string) (float64, bool) {
+	v := jp.Get(path)
+
+	if num, ok := jsonNumber(v.I) ; ok {
+		return num, true
+	}
+
+	return v.Float64(), false
+}
+
+// DeepGet walks the underlying value one segment at a time, treating string segments as object
+// keys and integer segments as array indices. If any segment can't be resolved, or is of any
+// other type, a zero-value JPath is returned.
+//
+//     jp.DeepGet("users", 2, "tags", 0)
+func (jp JPath) DeepGet(segments ...interface{}) JPath {
+	cur := jp
+
+	for _, seg := range segments {
+		if cur.I == nil {
+			return JPath{}
+		}
+
+		switch s := seg.(type) {
+		case string:
+			cur = cur.Field(s)
+
+		case int:
+			if s < 0 {
+				return JPath{}
+			}
+
+			cur = cur.Index(s)
+
+		default:
+			return JPath{}
+		}
+	}
+
+	return cur
+}
+
+// GetSep is like Get, but splits path on sep alone, with no escaping. It is useful when keys
+// may contain '.' or '/' but some other separator, such as "::", is known not to appear in
+// them. Empty segments are ignored. If sep is empty, the whole path is a single segment.
+func (jp JPath) GetSep(sep string, path string) JPath {
+	segments := []string{path}
+
+	if sep != "" {
+		segments = strings.Split(path, sep)
+	}
+
+	cur := jp
+
+	for _, seg := range segments {
+		if seg == "" {
+			continue
+		}
+
+		if cur.I == nil {
+			return JPath{}
+		}
+
+		cur = cur.child(seg)
+	}
+
+	return cur
+}
+
+// MissingSegmentError is returned by GetOrError when a path segment can't be resolved.
+type MissingSegmentError struct {
+	Path    string
+	Segment string
+	// Depth is the position of Segment within the path.
+	Depth int
+}
+
+func (e *MissingSegmentError) Error() string {
+	return fmt.Sprintf("jpath: segment %q (%d) of path %q could not be resolved", e.Segment, e.Depth, e.Path)
+}
+
+// AmbiguousSegmentError is returned by GetOrError when a numeric segment is applied to an object
+// whose keys are all integers, and reading the segment as a key selects a different member than
+// reading it as a position in the object's numerically-ordered keys. This is typical of arrays
+// which were serialized as objects with some elements missing, e.g. {"1": "a", "5": "b"}.
+type AmbiguousSegmentError struct {
+	Path    string
+	Segment string
+	// Depth is the position of Segment within the path.
+	Depth int
+	// Key is the value the segment resolves to when read as an object key.
+	Key JPath
+	// Index is the value the segment resolves to when read as a position.
+	Index JPath
+}
+
+func (e *AmbiguousSegmentError) Error() string {
+	return fmt.Sprintf("jpath: segment %q (%d) of path %q is both a key and an index of an integer-keyed object", e.Segment, e.Depth, e.Path)
+}
+
+// positional returns the member of obj at position i when its keys are ordered numerically. If
+// any key is not a non-negative integer, or i is out of range, ok is false.
+func positional(obj map[string]interface{}, i int) (key string, ok bool) {
+	if i < 0 || i >= len(obj) {
+		return "", false
+	}
+
+	keys := make([]int, 0, len(obj))
+
+	for k := range obj {
+		n, er := strconv.Atoi(k)
+		if er != nil || n < 0 || strconv.Itoa(n) != k {
+			return "", false
+		}
+
+		keys = append(keys, n)
+	}
+
+	sort.Ints(keys)
+	return strconv.Itoa(keys[i]), true
+}
+
+// GetOrError is like Get, but returns an error describing why the path couldn't be resolved
+// instead of a zero-value JPath. The error is a *MissingSegmentError when a segment doesn't
+// exist, or an *AmbiguousSegmentError when a numeric segment could select two different members
+// of an integer-keyed object. A path which resolves to an explicit null is not an error.
+func (jp JPath) GetOrError(path string) (JPath, error) {
+	cur := jp
+
+	for depth, seg := range splitPath(path) {
+		switch val := cur.I.(type) {
+		case map[string]interface{}:
+			if _, ok := val[seg] ; !ok {
+				return JPath{}, &MissingSegmentError{path, seg, depth}
+			}
+
+			if i, er := strconv.Atoi(seg) ; er == nil {
+				if key, ok := positional(val, i) ; ok && key != seg {
+					return JPath{}, &AmbiguousSegmentError{path, seg, depth, cur.Field(seg), cur.Field(key)}
+				}
+			}
+
+		case []interface{}:
+			if i, er := strconv.Atoi(seg) ; er != nil || i < 0 || i >= len(val) {
+				return JPath{}, &MissingSegmentError{path, seg, depth}
+			}
+
+		default:
+			return JPath{}, &MissingSegmentError{path, seg, depth}
+		}
+
+		cur = cur.child(seg)
+	}
+
+	return cur, nil
+}
+
+// PathInfo describes how far ResolveInfo got when resolving a path.
+type PathInfo struct {
+	// Segments are the segments the path was split into.
+	Segments []string
+	// Depth is the index of the deepest segment which was resolved, or -1 if not even the
+	// first segment could be resolved. A fully resolved path has Depth len(Segments)-1.
+	Depth int
+	// Types holds the JSON type ("object", "array", "string", ...) of the value reached by
+	// each resolved segment.
+	Types []string
+}
+
+// ResolveInfo is a diagnostic version of Get. Alongside the result, it reports how many
+// segments of the path were resolved and the JSON type of each value along the way, which
+// pinpoints where resolution stopped when the result is unexpectedly a zero-value.
+func (jp JPath) ResolveInfo(path string) (JPath, PathInfo) {
+	info := PathInfo{Segments: splitPath(path), Depth: -1, Types: []string{}}
+	cur := jp
+
+	for depth, seg := range info.Segments {
+		found := false
+
+		switch val := cur.I.(type) {
+		case map[string]interface{}:
+			_, found = val[seg]
+
+		case []interface{}:
+			i, er := strconv.Atoi(seg)
+			found = er == nil && i >= 0 && i < len(val)
+		}
+
+		if !found {
+			return JPath{}, info
+		}
+
+		cur = cur.child(seg)
+		info.Depth = depth
+		info.Types = append(info.Types, jsonKind(cur.I))
+	}
+
+	return cur, info
+}
+
+// MissingPaths returns those of required which resolve, as with Get, to nothing or to null, in
+// the order they were given. An empty result means every path is present.
+func (jp JPath) MissingPaths(required ...string) []string {
+	ret := []string{}
+
+	for _, path := range required {
+		if jp.Get(path).IsNull() {
+			ret = append(ret, path)
+		}
+	}
+
+	return ret
+}
+
+// CommonPath returns the longest path which is an ancestor of (or equal to) every one of paths,
+// in the dotted form accepted by Get. If the paths share no leading segments, or no paths are
+// given, an empty string is returned.
+func CommonPath(paths ...string) string {
+	if len(paths) == 0 {
+		return ""
+	}
+
+	common := splitPath(paths[0])
+
+	for _, path := range paths[1:] {
+		segments := splitPath(path)
+		n := 0
+
+		for n < len(common) && n < len(segments) && common[n] == segments[n] {
+			n += 1
+		}
+
+		common = common[:n]
+	}
+
+	return joinPath(common)
+}
+
+// CompiledPath is a path which has been split and validated ahead of time by Compile, for
+// resolving the same path against many values.
+type CompiledPath struct {
+	path     string
+	segments []string
+	// indices holds each segment's value as an array index, or -1 if it isn't one.
+	indices []int
+}
+
+// Compile splits path into segments once, as Get would, so it can be resolved repeatedly
+// without being re-parsed. An error is returned if path ends in an unterminated backslash
+// escape.
+func Compile(path string) (CompiledPath, error) {
+	trailing := 0
+
+	for i := len(path) - 1 ; i >= 0 && path[i] == '\\' ; i -= 1 {
+		trailing += 1
+	}
+
+	if trailing % 2 == 1 {
+		return CompiledPath{}, fmt.Errorf("jpath: path %q ends in an unterminated escape", path)
+	}
+
+	cp := CompiledPath{path: path, segments: splitPath(path)}
+	cp.indices = make([]int, len(cp.segments))
+
+	for i, seg := range cp.segments {
+		if n, er := strconv.Atoi(seg) ; er == nil && n >= 0 {
+			cp.indices[i] = n
+		} else {
+			cp.indices[i] = -1
+		}
+	}
+
+	return cp, nil
+}
+
+// String returns the path cp was compiled from.
+func (cp CompiledPath) String() string {
+	return cp.path
+}
+
+// Resolve returns the same result as jp.Get for the path cp was compiled from.
+func (cp CompiledPath) Resolve(jp JPath) JPath {
+	cur := jp
+
+	for i, seg := range cp.segments {
+		if cur.I == nil {
+			return JPath{}
+		}
+
+		if _, ok := cur.I.([]interface{}) ; ok {
+			if cp.indices[i] < 0 {
+				return JPath{}
+			}
+
+			cur = cur.Index(cp.indices[i])
+		} else {
+			cur = cur.Field(seg)
+		}
+	}
+
+	return cur
+}
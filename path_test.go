@@ -0,0 +1,271 @@
+package jpath
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDeepGet(t *testing.T) {
+	var jp JPath
+	jsonBlob := `{
+		"users": [
+			{"name": "alice", "tags": ["admin"]},
+			{"name": "bob", "tags": []},
+			{"name": "carol", "tags": ["ops", "dev"]}
+		]
+	}`
+
+	if er := jp.ParseString(jsonBlob) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if tag := jp.DeepGet("users", 2, "tags", 0).String() ; tag != "ops" {
+		t.Errorf("Expected ops, got %s", tag)
+	}
+
+	if name := jp.DeepGet("users", 0, "name").String() ; name != "alice" {
+		t.Errorf("Expected alice, got %s", name)
+	}
+
+	if !jp.DeepGet("users", 3, "name").IsNull() {
+		t.Errorf("Expected zero-value for out-of-range index")
+	}
+
+	if !jp.DeepGet("users", -1).IsNull() {
+		t.Errorf("Expected zero-value for negative index")
+	}
+
+	if !jp.DeepGet("users", "0").IsNull() {
+		t.Errorf("Expected zero-value for string segment on array")
+	}
+}
+
+func TestGet(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`{"a": {"b": {"c": 42}}, "list": [{"x": 1}, {"x": 2}], "v1.2": {"ok": true}}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	for _, path := range []string{"a.b.c", "a/b/c", "/a/b/c/", "a/b.c", ".a.b.c."} {
+		if val := jp.Get(path).Int() ; val != 42 {
+			t.Errorf("Expected %s to resolve to 42, got %d", path, val)
+		}
+	}
+
+	if val := jp.Get("list/1/x").Int() ; val != 2 {
+		t.Errorf("Expected list/1/x to resolve to 2, got %d", val)
+	}
+
+	if jp.Get(`v1\.2.ok`).I != true {
+		t.Errorf(`Expected v1\.2.ok to resolve through an escaped separator`)
+	}
+
+	if !jp.Get("a.missing.c").IsNull() {
+		t.Errorf("Expected zero-value for a missing path")
+	}
+
+	if !jp.Get("list.x").IsNull() {
+		t.Errorf("Expected zero-value for a non-numeric array segment")
+	}
+}
+
+func TestGetNumber(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`{"stats": {"count": 42, "ratio": "0.5", "name": "bob"}}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if num, ok := jp.GetNumber("stats.count") ; num != 42 || !ok {
+		t.Errorf("Expected (42, true) for a real number, got (%v, %v)", num, ok)
+	}
+
+	if num, ok := jp.GetNumber("stats.ratio") ; num != 0.5 || ok {
+		t.Errorf("Expected (0.5, false) for a numeric string, got (%v, %v)", num, ok)
+	}
+
+	if num, ok := jp.GetNumber("stats.missing") ; num != 0 || ok {
+		t.Errorf("Expected (0, false) for a missing path, got (%v, %v)", num, ok)
+	}
+}
+
+func TestGetOrError(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`{"list": [10, 20], "sparse": {"1": "a", "5": "b"}, "dense": {"0": "x", "1": "y"}}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if val, er := jp.GetOrError("list.1") ; er != nil || val.Int() != 20 {
+		t.Errorf("Expected 20, got %v (%v)", val.I, er)
+	}
+
+	if val, er := jp.GetOrError("dense.1") ; er != nil || val.String() != "y" {
+		t.Errorf("Expected y, got %v (%v)", val.I, er)
+	}
+
+	_, er := jp.GetOrError("list.5")
+	if missing, ok := er.(*MissingSegmentError) ; !ok || missing.Depth != 1 {
+		t.Errorf("Expected a *MissingSegmentError at depth 1, got %#v", er)
+	}
+
+	_, er = jp.GetOrError("sparse.1")
+	ambiguous, ok := er.(*AmbiguousSegmentError)
+
+	if !ok {
+		t.Fatalf("Expected an *AmbiguousSegmentError, got %#v", er)
+	}
+
+	if ambiguous.Key.String() != "a" || ambiguous.Index.String() != "b" {
+		t.Errorf("Expected the key to resolve to a and the index to b, got %v and %v", ambiguous.Key.I, ambiguous.Index.I)
+	}
+}
+
+func TestResolveInfo(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`{"users": [{"name": "alice"}]}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	val, info := jp.ResolveInfo("users.0.name")
+
+	if val.String() != "alice" {
+		t.Errorf("Expected alice, got %#v", val.I)
+	}
+
+	if info.Depth != 2 {
+		t.Errorf("Expected depth 2, got %d", info.Depth)
+	}
+
+	expectedTypes := []string{"array", "object", "string"}
+
+	if len(info.Types) != len(expectedTypes) {
+		t.Fatalf("Expected types %v, got %v", expectedTypes, info.Types)
+	}
+
+	for i, kind := range expectedTypes {
+		if info.Types[i] != kind {
+			t.Errorf("Expected type %d to be %s, got %s", i, kind, info.Types[i])
+		}
+	}
+
+	val, info = jp.ResolveInfo("users.name.first")
+
+	if !val.IsNull() {
+		t.Errorf("Expected a zero-value, got %#v", val.I)
+	}
+
+	if info.Depth != 0 || len(info.Types) != 1 || info.Types[0] != "array" {
+		t.Errorf("Expected resolution to stop after the first segment, got %+v", info)
+	}
+}
+
+func TestMissingPaths(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`{"name": "bob", "address": {"city": "Paris", "zip": null}, "tags": ["a"], "active": false}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	missing := jp.MissingPaths("name", "address.zip", "tags.0", "phone", "active", "address.street")
+
+	if fmt.Sprint(missing) != "[address.zip phone address.street]" {
+		t.Errorf("Expected [address.zip phone address.street], got %v", missing)
+	}
+
+	if missing := jp.MissingPaths("name", "address.city", "tags.0", "active") ; missing == nil || len(missing) != 0 {
+		t.Errorf("Expected nothing to be missing, got %v", missing)
+	}
+}
+
+func TestCommonPath(t *testing.T) {
+	if common := CommonPath("users.0.address.city", "users.0.address.zip", "users.0.name") ; common != "users.0" {
+		t.Errorf("Expected users.0, got %q", common)
+	}
+
+	if common := CommonPath("a.b.c", "a/b/d") ; common != "a.b" {
+		t.Errorf("Expected a.b, got %q", common)
+	}
+
+	if common := CommonPath(`v1\.2.x`, `v1\.2.y`) ; common != `v1\.2` {
+		t.Errorf(`Expected v1\.2, got %q`, common)
+	}
+
+	if common := CommonPath("a.b", "c.d") ; common != "" {
+		t.Errorf("Expected no common path, got %q", common)
+	}
+}
+
+func TestCompile(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`{"users": [{"name": "alice", "v1.2": 7}, {"name": "bob"}]}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	for _, path := range []string{"users.1.name", "users/0/name", `users.0.v1\.2`, "users.x", "users.5.name", "missing"} {
+		cp, er := Compile(path)
+		if er != nil {
+			t.Fatal(er)
+		}
+
+		if compiled, get := cp.Resolve(jp), jp.Get(path) ; compiled.I != get.I {
+			t.Errorf("Expected Resolve and Get to agree on %s, got %#v and %#v", path, compiled.I, get.I)
+		}
+	}
+
+	if _, er := Compile(`users.0\`) ; er == nil {
+		t.Errorf("Expected an error for an unterminated escape")
+	}
+}
+
+func BenchmarkGet(b *testing.B) {
+	var jp JPath
+
+	if er := jp.ParseString(`{"a": {"b": [{"c": {"d": 1}}]}}`) ; er != nil {
+		b.Fatal(er)
+	}
+
+	for i := 0 ; i < b.N ; i += 1 {
+		_ = jp.Get("a.b.0.c.d")
+	}
+}
+
+func BenchmarkCompiledPath(b *testing.B) {
+	var jp JPath
+
+	if er := jp.ParseString(`{"a": {"b": [{"c": {"d": 1}}]}}`) ; er != nil {
+		b.Fatal(er)
+	}
+
+	cp, er := Compile("a.b.0.c.d")
+	if er != nil {
+		b.Fatal(er)
+	}
+
+	for i := 0 ; i < b.N ; i += 1 {
+		_ = cp.Resolve(jp)
+	}
+}
+
+func TestGetSep(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`{"example.com": {"v1.2": {"paths": ["/a/b", "/c"]}}}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if path := jp.GetSep("/", "example.com/v1.2/paths/0").String() ; path != "/a/b" {
+		t.Errorf("Expected /a/b, got %q", path)
+	}
+
+	if path := jp.GetSep("::", "example.com::v1.2::paths::1").String() ; path != "/c" {
+		t.Errorf("Expected /c, got %q", path)
+	}
+
+	if !jp.GetSep("/", "example.com/v2").IsNull() {
+		t.Errorf("Expected a zero-value for a missing path")
+	}
+}
@@ -0,0 +1,130 @@
+package jpath
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// pointerEscaper escapes a reference token of a JSON Pointer, as in RFC 6901.
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// pointerUnescaper reverses pointerEscaper.
+var pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+
+// splitPointer splits an RFC 6901 JSON Pointer into its unescaped reference tokens. The empty
+// pointer, which refers to the whole document, has no tokens.
+func splitPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+
+	if pointer[0] != '/' {
+		return nil, fmt.Errorf("jpath: JSON Pointer %q does not start with '/'", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+
+	for i, tok := range tokens {
+		tokens[i] = pointerUnescaper.Replace(tok)
+	}
+
+	return tokens, nil
+}
+
+// joinPointer is the inverse of splitPointer.
+func joinPointer(tokens []string) string {
+	ret := strings.Builder{}
+
+	for _, tok := range tokens {
+		ret.WriteByte('/')
+		ret.WriteString(pointerEscaper.Replace(tok))
+	}
+
+	return ret.String()
+}
+
+// LeafPointers returns the RFC 6901 JSON Pointers of every leaf within the underlying value.
+// Leaves are scalars and empty objects or arrays. Pointers are ordered as Walk would visit them:
+// object fields in sorted order and array elements in index order. A scalar receiver has the
+// single leaf "".
+func (jp JPath) LeafPointers() []string {
+	return leafPointers([]string{}, jp.I, "")
+}
+
+func leafPointers(ret []string, v interface{}, pointer string) []string {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if len(val) == 0 {
+			break
+		}
+
+		for _, k := range (JPath{I: val}).SortedFields() {
+			ret = leafPointers(ret, val[k], pointer + "/" + pointerEscaper.Replace(k))
+		}
+
+		return ret
+
+	case []interface{}:
+		if len(val) == 0 {
+			break
+		}
+
+		for i, elem := range val {
+			ret = leafPointers(ret, elem, pointer + "/" + strconv.Itoa(i))
+		}
+
+		return ret
+	}
+
+	return append(ret, pointer)
+}
+
+// Pointer resolves an RFC 6901 JSON Pointer such as "/users/2/name" against the underlying value.
+// Unlike Get, array indices must be written canonically (no leading zeros or signs), and "~1"
+// and "~0" stand for '/' and '~' within a token. The empty pointer refers to the receiver. If
+// the pointer is malformed or can't be resolved, a zero-value JPath is returned.
+func (jp JPath) Pointer(pointer string) JPath {
+	tokens, er := splitPointer(pointer)
+	if er != nil {
+		return JPath{}
+	}
+
+	cur := jp
+
+	for _, tok := range tokens {
+		switch val := cur.I.(type) {
+		case map[string]interface{}:
+			if _, ok := val[tok] ; !ok {
+				return JPath{}
+			}
+
+			cur = cur.Field(tok)
+
+		case []interface{}:
+			i, er := arrayIndex(val, tok, false)
+			if er != nil {
+				return JPath{}
+			}
+
+			cur = cur.Index(i)
+
+		default:
+			return JPath{}
+		}
+	}
+
+	return cur
+}
+
+// Pointers resolves each of the given JSON Pointers as Pointer does, returning the results in the
+// same order. Pointers which can't be resolved yield zero-value JPaths.
+func (jp JPath) Pointers(pointers ...string) []JPath {
+	ret := make([]JPath, len(pointers))
+
+	for i, pointer := range pointers {
+		ret[i] = jp.Pointer(pointer)
+	}
+
+	return ret
+}
@@ -0,0 +1,61 @@
+package jpath
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLeafPointers(t *testing.T) {
+	var jp JPath
+	jsonBlob := `{
+		"b": {"y": 1, "x": [true, null]},
+		"a/b": "slash",
+		"m~n": "tilde",
+		"empty": {},
+		"list": [[], 2]
+	}`
+
+	if er := jp.ParseString(jsonBlob) ; er != nil {
+		t.Fatal(er)
+	}
+
+	expected := "[/a~1b /b/x/0 /b/x/1 /b/y /empty /list/0 /list/1 /m~0n]"
+
+	if pointers := jp.LeafPointers() ; fmt.Sprint(pointers) != expected {
+		t.Errorf("Expected %s, got %v", expected, pointers)
+	}
+
+	if pointers := jp.Field("a/b").LeafPointers() ; len(pointers) != 1 || pointers[0] != "" {
+		t.Errorf(`Expected [""] for a scalar, got %q`, pointers)
+	}
+}
+
+func TestResolvePointers(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`{"users": [{"name": "bob"}, {"name": "alice"}], "a/b": {"m~n": 3}}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	results := jp.Pointers("/users/1/name", "/users/2/name", "/a~1b/m~0n", "/users/01/name", "")
+
+	if len(results) != 5 {
+		t.Fatalf("Expected 5 results, got %d", len(results))
+	}
+
+	if name := results[0].String() ; name != "alice" {
+		t.Errorf("Expected alice, got %q", name)
+	}
+
+	if !results[1].IsUndefined() || !results[3].IsUndefined() {
+		t.Errorf("Expected unresolvable pointers to yield zero-values, got %#v and %#v", results[1].I, results[3].I)
+	}
+
+	if n := results[2].Int() ; n != 3 {
+		t.Errorf("Expected 3 through escaped tokens, got %d", n)
+	}
+
+	if results[4].Length() != 0 || len(results[4].Fields()) != 2 {
+		t.Errorf("Expected the empty pointer to refer to the whole document, got %#v", results[4].I)
+	}
+}
@@ -0,0 +1,83 @@
+package jpath
+
+import (
+	"strconv"
+)
+
+// setPath stores value at the path given by segments within v, modifying v in place, and
+// returns the new root. Missing objects along the path are created; numeric segments index into
+// existing arrays. If the path runs through a scalar or past the end of an array, v is returned
+// untouched and ok is false.
+func setPath(v interface{}, segments []string, value interface{}) (ret interface{}, ok bool) {
+	if len(segments) == 0 {
+		return value, true
+	}
+
+	seg := segments[0]
+
+	switch val := v.(type) {
+	case nil:
+		child, _ := setPath(nil, segments[1:], value)
+		return map[string]interface{}{seg: child}, true
+
+	case map[string]interface{}:
+		child, ok := setPath(val[seg], segments[1:], value)
+		if ok {
+			val[seg] = child
+		}
+
+		return val, ok
+
+	case []interface{}:
+		i, er := strconv.Atoi(seg)
+		if er != nil || i < 0 || i >= len(val) {
+			return v, false
+		}
+
+		child, ok := setPath(val[i], segments[1:], value)
+		if ok {
+			val[i] = child
+		}
+
+		return val, ok
+	}
+
+	return v, false
+}
+
+// SetIfAbsent returns a new JPath wrapping a deep copy of the underlying value with value stored
+// at path, but only if nothing (or null) is currently stored there. Missing objects along the
+// path are created. The returned bool reports whether value was written; if it wasn't, the
+// returned JPath is equivalent to the receiver. The receiver is never modified.
+func (jp JPath) SetIfAbsent(path string, value interface{}) (JPath, bool) {
+	if !jp.Get(path).IsNull() {
+		return jp, false
+	}
+
+	ret, ok := setPath(deepCopy(jp.I), splitPath(path), deepCopy(value))
+	if !ok {
+		return jp, false
+	}
+
+	return JPath{I: ret}, true
+}
+
+// Update returns a new JPath wrapping a deep copy of the underlying value in which the value at
+// path has been replaced by the result of calling fn on it. If path doesn't exist, fn is not
+// called and the returned JPath is equivalent to the receiver. The receiver is never modified.
+//
+//     jp.Update("stats.count", func(v JPath) interface{} { return v.Int() + 1 })
+func (jp JPath) Update(path string, fn func(JPath) interface{}) JPath {
+	cur, info := jp.ResolveInfo(path)
+
+	if info.Depth != len(info.Segments) - 1 {
+		return jp
+	}
+
+	ret, ok := setPath(deepCopy(jp.I), info.Segments, deepCopy(fn(cur)))
+	if !ok {
+		return jp
+	}
+
+	return JPath{I: ret}
+}
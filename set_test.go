@@ -0,0 +1,85 @@
+package jpath
+
+import "testing"
+
+func TestSetIfAbsent(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`{"server": {"port": 8080, "host": null}}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	withTimeout, wrote := jp.SetIfAbsent("server.timeout.read", 30)
+
+	if !wrote {
+		t.Errorf("Expected the missing path to be written")
+	}
+
+	if timeout := withTimeout.Get("server.timeout.read").Int() ; timeout != 30 {
+		t.Errorf("Expected 30, got %d", timeout)
+	}
+
+	if !jp.Get("server.timeout").IsNull() {
+		t.Errorf("Original value was modified")
+	}
+
+	withHost, wrote := jp.SetIfAbsent("server.host", "localhost")
+
+	if !wrote || withHost.Get("server.host").String() != "localhost" {
+		t.Errorf("Expected the null host to be written")
+	}
+
+	withPort, wrote := jp.SetIfAbsent("server.port", 80)
+
+	if wrote {
+		t.Errorf("Did not expect a present value to be written")
+	}
+
+	if port := withPort.Get("server.port").Int() ; port != 8080 {
+		t.Errorf("Expected 8080, got %d", port)
+	}
+
+	if _, wrote := jp.SetIfAbsent("server.port.number", 80) ; wrote {
+		t.Errorf("Did not expect a path through a scalar to be written")
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`{"stats": {"count": 41, "tags": ["a"]}}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	increment := func(v JPath) interface{} {
+		return v.Int() + 1
+	}
+
+	updated := jp.Update("stats.count", increment)
+
+	if count := updated.Get("stats.count").Int() ; count != 42 {
+		t.Errorf("Expected 42, got %d", count)
+	}
+
+	if count := jp.Get("stats.count").Int() ; count != 41 {
+		t.Errorf("Original value was modified, got %d", count)
+	}
+
+	upper := jp.Update("stats.tags.0", func(v JPath) interface{} {
+		return v.String() + "!"
+	})
+
+	if tag := upper.Get("stats.tags.0").String() ; tag != "a!" {
+		t.Errorf("Expected a!, got %s", tag)
+	}
+
+	called := false
+	missing := jp.Update("stats.missing", func(v JPath) interface{} {
+		called = true
+		return 1
+	})
+
+	if called || !missing.Get("stats.missing").IsNull() {
+		t.Errorf("Expected a missing path to be a no-op")
+	}
+}
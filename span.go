@@ -0,0 +1,149 @@
+package jpath
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// spanNode records the byte offsets of a parsed value and, for objects and arrays, of its
+// children.
+type spanNode struct {
+	start, end int
+	fields     map[string]*spanNode
+	elems      []*spanNode
+}
+
+// field returns the span of the named field, or nil if it is unknown. It is safe to call on a
+// nil *spanNode.
+func (sn *spanNode) field(name string) *spanNode {
+	if sn == nil {
+		return nil
+	}
+
+	return sn.fields[name]
+}
+
+// index returns the span of the ith element, or nil if it is unknown. It is safe to call on a
+// nil *spanNode.
+func (sn *spanNode) index(i int) *spanNode {
+	if sn == nil || i < 0 || i >= len(sn.elems) {
+		return nil
+	}
+
+	return sn.elems[i]
+}
+
+// ParseBytesWithSpans parses the bytes as JSON like ParseBytes, additionally recording where
+// each value appears in them. The result is the root of a Tracked tree: its source position, and
+// that of any Tracked reached from it with Field, Index or Get, is available from Span.
+func ParseBytesWithSpans(data []byte) (Tracked, error) {
+	body := stripBOM(data)
+	sp := spanParser{dec: json.NewDecoder(bytes.NewReader(body)), src: body, base: len(data) - len(body)}
+
+	val, spans, er := sp.parseValue()
+	if er != nil {
+		return Tracked{}, er
+	}
+
+	if _, er := sp.dec.Token() ; er != io.EOF {
+		return Tracked{}, fmt.Errorf("jpath: unexpected data after top-level value")
+	}
+
+	return Tracked{JPath: JPath{I: val}, spans: spans}, nil
+}
+
+// Span returns the byte offsets in the input to ParseBytesWithSpans at which the underlying
+// value starts and ends, such that input[start:end] is its source text. If the position isn't
+// known, because the Tracked wasn't parsed by ParseBytesWithSpans or doesn't exist in the input,
+// both offsets are -1.
+func (t Tracked) Span() (start, end int) {
+	if t.spans == nil {
+		return -1, -1
+	}
+
+	return t.spans.start, t.spans.end
+}
+
+// spanParser builds a tree of values alongside their spans from a json.Decoder's token stream.
+// The decoder reports where each token ends; where it starts is found by skipping the
+// whitespace and separators which precede it in src.
+type spanParser struct {
+	dec *json.Decoder
+	src []byte
+	// base is the offset of src within the original input, which differs when a byte order mark
+	// was stripped.
+	base int
+}
+
+// next returns the next token along with its starting and ending offsets.
+func (sp *spanParser) next() (tok json.Token, start, end int, er error) {
+	start = int(sp.dec.InputOffset())
+
+	if tok, er = sp.dec.Token() ; er != nil {
+		return
+	}
+
+	for start < len(sp.src) && bytes.IndexByte([]byte(" \t\r\n,:"), sp.src[start]) >= 0 {
+		start += 1
+	}
+
+	return tok, sp.base + start, sp.base + int(sp.dec.InputOffset()), nil
+}
+
+func (sp *spanParser) parseValue() (interface{}, *spanNode, error) {
+	tok, start, end, er := sp.next()
+	if er != nil {
+		return nil, nil, er
+	}
+
+	switch tok {
+	case json.Delim('{'):
+		obj := map[string]interface{}{}
+		node := &spanNode{start: start, fields: map[string]*spanNode{}}
+
+		for sp.dec.More() {
+			key, _, _, er := sp.next()
+			if er != nil {
+				return nil, nil, er
+			}
+
+			val, child, er := sp.parseValue()
+			if er != nil {
+				return nil, nil, er
+			}
+
+			obj[key.(string)] = val
+			node.fields[key.(string)] = child
+		}
+
+		if _, _, node.end, er = sp.next() ; er != nil {
+			return nil, nil, er
+		}
+
+		return obj, node, nil
+
+	case json.Delim('['):
+		ary := []interface{}{}
+		node := &spanNode{start: start, elems: []*spanNode{}}
+
+		for sp.dec.More() {
+			val, child, er := sp.parseValue()
+			if er != nil {
+				return nil, nil, er
+			}
+
+			ary = append(ary, val)
+			node.elems = append(node.elems, child)
+		}
+
+		if _, _, node.end, er = sp.next() ; er != nil {
+			return nil, nil, er
+		}
+
+		return ary, node, nil
+	}
+
+	return tok, &spanNode{start: start, end: end}, nil
+}
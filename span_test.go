@@ -0,0 +1,65 @@
+package jpath
+
+import "testing"
+
+func TestParseBytesWithSpans(t *testing.T) {
+	input := []byte(`{
+	"name": "widget",
+	"size": 12.5,
+	"tags": ["a", {"deep": true}],
+	"escaped": "say \"hi\""
+}`)
+
+	jp, er := ParseBytesWithSpans(input)
+	if er != nil {
+		t.Fatal(er)
+	}
+
+	expected := map[string]string{
+		"":            string(input),
+		"name":        `"widget"`,
+		"size":        `12.5`,
+		"tags":        `["a", {"deep": true}]`,
+		"tags.0":      `"a"`,
+		"tags.1":      `{"deep": true}`,
+		"tags.1.deep": `true`,
+		"escaped":     `"say \"hi\""`,
+	}
+
+	for path, text := range expected {
+		start, end := jp.Get(path).Span()
+
+		if start < 0 || string(input[start:end]) != text {
+			t.Errorf("Expected span of %q to cover %s, got [%d:%d]", path, text, start, end)
+		}
+	}
+
+	if name := jp.Field("name").String() ; name != "widget" {
+		t.Errorf("Expected widget, got %s", name)
+	}
+
+	if start, end := jp.Field("missing").Span() ; start != -1 || end != -1 {
+		t.Errorf("Expected no span for a missing field, got [%d:%d]", start, end)
+	}
+
+	if start, end := jp.JPath.WithParents().Field("name").Span() ; start != -1 || end != -1 {
+		t.Errorf("Expected no span for a Tracked not parsed with spans, got [%d:%d]", start, end)
+	}
+
+	if start, end := jp.Get("tags.1.deep").Parent().Span() ; string(input[start:end]) != `{"deep": true}` {
+		t.Errorf("Expected a parent to keep its span, got [%d:%d]", start, end)
+	}
+}
+
+func TestParseBytesWithSpansBOM(t *testing.T) {
+	input := append([]byte{0xEF, 0xBB, 0xBF}, `{"a": 1}`...)
+
+	jp, er := ParseBytesWithSpans(input)
+	if er != nil {
+		t.Fatal(er)
+	}
+
+	if start, end := jp.Field("a").Span() ; string(input[start:end]) != "1" {
+		t.Errorf("Expected spans to be relative to the original input, got [%d:%d]", start, end)
+	}
+}
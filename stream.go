@@ -0,0 +1,173 @@
+package jpath
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// skipValue consumes the next complete value from dec, without retaining it.
+func skipValue(dec *json.Decoder) error {
+	depth := 0
+
+	for {
+		tok, er := dec.Token()
+		if er != nil {
+			return er
+		}
+
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth += 1
+		case json.Delim('}'), json.Delim(']'):
+			depth -= 1
+		}
+
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+// CountStream counts the elements of the top-level array, or the keys of the top-level object,
+// read from r, without building the document in memory. An error is returned if the input is
+// malformed or its top-level value is not an array or object.
+func CountStream(r io.Reader) (int, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+
+	tok, er := dec.Token()
+	if er != nil {
+		return 0, er
+	}
+
+	delim, ok := tok.(json.Delim)
+	if !ok || (delim != '[' && delim != '{') {
+		return 0, fmt.Errorf("jpath: top-level value is not an array or object")
+	}
+
+	count := 0
+
+	for dec.More() {
+		if delim == '{' {
+			// Consume the key; the value is skipped below.
+			if _, er := dec.Token() ; er != nil {
+				return 0, er
+			}
+		}
+
+		if er := skipValue(dec) ; er != nil {
+			return 0, er
+		}
+
+		count += 1
+	}
+
+	if _, er := dec.Token() ; er != nil {
+		return 0, er
+	}
+
+	return count, nil
+}
+
+// ParseDecoder decodes the next value from dec and overwrites the underlying value with it,
+// leaving dec positioned after that value. If dec has UseNumber set, numbers are stored as
+// json.Number rather than float64.
+func (jp *JPath) ParseDecoder(dec *json.Decoder) error {
+	var val interface{}
+
+	if er := dec.Decode(&val) ; er != nil {
+		return er
+	}
+
+	*jp = JPath{I: val}
+	return nil
+}
+
+// StreamTransform reads a single JSON document from r and writes it to w with every leaf -- every
+// value other than an object or array -- replaced by the result of calling fn with its path and
+// value, as Transform does. The document is processed a token at a time, so only the current
+// leaf is held in memory. Objects keep the key order of the input and the output is compact.
+// Numbers are passed to fn as float64, as with ParseBytes. An error is returned if r holds
+// malformed JSON, more than one value, or if a transformed leaf can't be marshaled; in that case
+// w may have received a partial document.
+func StreamTransform(r io.Reader, w io.Writer, fn func(path string, v JPath) interface{}) error {
+	dec := json.NewDecoder(r)
+
+	if er := streamTransform(dec, w, []string{}, fn) ; er != nil {
+		return er
+	}
+
+	if _, er := dec.Token() ; er == nil {
+		return fmt.Errorf("jpath: unexpected data after the top-level value")
+	} else if er != io.EOF {
+		return er
+	}
+
+	return nil
+}
+
+func streamTransform(dec *json.Decoder, w io.Writer, segments []string, fn func(path string, v JPath) interface{}) error {
+	tok, er := dec.Token()
+	if er != nil {
+		return er
+	}
+
+	delim, ok := tok.(json.Delim)
+
+	if !ok {
+		bytes, er := (JPath{I: fn(joinPath(segments), JPath{I: tok})}).MarshalJSON()
+		if er != nil {
+			return er
+		}
+
+		_, er = w.Write(bytes)
+		return er
+	}
+
+	closing := "]"
+	if delim == '{' {
+		closing = "}"
+	}
+
+	if _, er := io.WriteString(w, delim.String()) ; er != nil {
+		return er
+	}
+
+	for i := 0 ; dec.More() ; i += 1 {
+		if i > 0 {
+			if _, er := io.WriteString(w, ",") ; er != nil {
+				return er
+			}
+		}
+
+		seg := strconv.Itoa(i)
+
+		if delim == '{' {
+			key, er := dec.Token()
+			if er != nil {
+				return er
+			}
+
+			seg = key.(string)
+
+			bytes, _ := json.Marshal(seg)
+			if _, er := w.Write(append(bytes, ':')) ; er != nil {
+				return er
+			}
+		}
+
+		if er := streamTransform(dec, w, append(segments[:len(segments):len(segments)], seg), fn) ; er != nil {
+			return er
+		}
+	}
+
+	// Consume the closing delimiter.
+	if _, er := dec.Token() ; er != nil {
+		return er
+	}
+
+	_, er = io.WriteString(w, closing)
+	return er
+}
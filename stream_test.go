@@ -0,0 +1,129 @@
+package jpath
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCountStream(t *testing.T) {
+	const n = 10000
+
+	buf := strings.Builder{}
+	buf.WriteString("[")
+
+	for i := 0 ; i < n ; i += 1 {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+
+		fmt.Fprintf(&buf, `{"id": %d, "tags": ["a", "b"], "nested": {"x": [1, [2]]}}`, i)
+	}
+
+	buf.WriteString("]")
+
+	count, er := CountStream(strings.NewReader(buf.String()))
+	if er != nil {
+		t.Fatal(er)
+	}
+
+	if count != n {
+		t.Errorf("Expected %d elements, got %d", n, count)
+	}
+
+	if count, er := CountStream(strings.NewReader(`{"a": [1, 2], "b": {}, "c": null}`)) ; er != nil || count != 3 {
+		t.Errorf("Expected 3 keys, got %d (%v)", count, er)
+	}
+
+	if _, er := CountStream(strings.NewReader(`"scalar"`)) ; er == nil {
+		t.Errorf("Expected an error for a scalar root")
+	}
+
+	if _, er := CountStream(strings.NewReader(`[1, 2`)) ; er == nil {
+		t.Errorf("Expected an error for malformed input")
+	}
+}
+
+func TestParseDecoder(t *testing.T) {
+	dec := json.NewDecoder(strings.NewReader(`{"id": 1} {"id": 12345678901234567890}`))
+	dec.UseNumber()
+
+	var first, second JPath
+
+	if er := first.ParseDecoder(dec) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if er := second.ParseDecoder(dec) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if id := first.Field("id").Int() ; id != 1 {
+		t.Errorf("Expected 1, got %d", id)
+	}
+
+	if _, ok := second.Field("id").I.(json.Number) ; !ok {
+		t.Errorf("Expected a json.Number, got %#v", second.Field("id").I)
+	}
+
+	if id := second.Field("id").Uint64() ; id != 12345678901234567890 {
+		t.Errorf("Expected 12345678901234567890, got %d", id)
+	}
+}
+
+func TestStreamTransform(t *testing.T) {
+	buf := strings.Builder{}
+	buf.WriteString(`{"users": [`)
+
+	for i := 0 ; i < 500 ; i += 1 {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+
+		fmt.Fprintf(&buf, `{"id": %d, "email": "user%d@example.com", "tags": ["a", "b"], "meta": {"active": %v, "note": null, "empty": {}}}`, i, i, i % 2 == 0)
+	}
+
+	buf.WriteString(`], "count": 500}`)
+
+	redact := func(path string, v JPath) interface{} {
+		if strings.HasSuffix(path, ".email") {
+			return "<redacted>"
+		}
+
+		return v.I
+	}
+
+	out := strings.Builder{}
+
+	if er := StreamTransform(strings.NewReader(buf.String()), &out, redact) ; er != nil {
+		t.Fatal(er)
+	}
+
+	var jp, streamed JPath
+
+	if er := jp.ParseString(buf.String()) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if er := streamed.ParseString(out.String()) ; er != nil {
+		t.Fatalf("Expected valid JSON output, got %v", er)
+	}
+
+	if expected := jp.Transform(redact) ; !reflect.DeepEqual(streamed.I, expected.I) {
+		t.Errorf("Expected the streamed result to match Transform")
+	}
+
+	if email := streamed.Get("users.3.email").String() ; email != "<redacted>" {
+		t.Errorf("Expected the email to be redacted, got %q", email)
+	}
+
+	if er := StreamTransform(strings.NewReader(`[1, 2`), &strings.Builder{}, redact) ; er == nil {
+		t.Errorf("Expected an error for malformed input")
+	}
+
+	if er := StreamTransform(strings.NewReader(`1 2`), &strings.Builder{}, redact) ; er == nil {
+		t.Errorf("Expected an error for trailing data")
+	}
+}
@@ -0,0 +1,87 @@
+package jpath
+
+import (
+	"math"
+	"time"
+)
+
+// EpochTime interprets the underlying number as a Unix timestamp, guessing its precision from
+// its magnitude:
+//
+//     |n| < 1e11          seconds       (covers dates until the year 5138)
+//     |n| < 1e14          milliseconds
+//     |n| < 1e17          microseconds
+//     otherwise           nanoseconds
+//
+// Fractional seconds are preserved. If the underlying value is not a number, or is too large to
+// represent, the zero time is returned.
+func (jp JPath) EpochTime() time.Time {
+	num, ok := jsonNumber(jp.I)
+
+	if !ok || math.IsNaN(num) || math.IsInf(num, 0) {
+		return time.Time{}
+	}
+
+	// perSec is the number of units per second at the guessed precision. Whole seconds and the
+	// nanosecond remainder are computed separately, so instants past 2262 -- beyond the reach of
+	// int64 nanoseconds -- don't overflow.
+	var perSec float64
+
+	switch abs := math.Abs(num) ; {
+	case abs < 1e11:
+		perSec = 1
+	case abs < 1e14:
+		perSec = 1e3
+	case abs < 1e17:
+		perSec = 1e6
+	default:
+		perSec = 1e9
+	}
+
+	sec := math.Floor(num / perSec)
+
+	if sec < math.MinInt64 || sec >= math.MaxInt64 {
+		return time.Time{}
+	}
+
+	nsec := math.Round((num - sec * perSec) * (1e9 / perSec))
+
+	return time.Unix(int64(sec), int64(nsec))
+}
+
+// Time converts the underlying value to a time.Time. Strings are parsed with each of layouts in
+// turn, as time.Parse does, and the first successful result is returned; if no layouts are given,
+// time.RFC3339Nano is used, which also accepts RFC 3339 times without fractional seconds. Numbers
+// are interpreted as Unix timestamps by EpochTime. If the value can't be converted, the zero time
+// is returned.
+func (jp JPath) Time(layouts ...string) time.Time {
+	str, ok := jp.I.(string)
+	if !ok {
+		return jp.EpochTime()
+	}
+
+	if len(layouts) == 0 {
+		layouts = []string{time.RFC3339Nano}
+	}
+
+	for _, layout := range layouts {
+		if t, er := time.Parse(layout, str) ; er == nil {
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// TimeSlice converts each element of the underlying array with Time, returning the zero time for
+// elements which can't be converted. If the underlying value is not an array, an empty slice is
+// returned.
+func (jp JPath) TimeSlice(layouts ...string) []time.Time {
+	ret := make([]time.Time, jp.Length())
+
+	for i := range ret {
+		ret[i] = jp.Index(i).Time(layouts...)
+	}
+
+	return ret
+}
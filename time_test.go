@@ -0,0 +1,140 @@
+package jpath
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEpochTime(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`[1700000000, 1700000000000, 1700000000000000, 1700000000000000000, "1700000000"]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	expected := time.Unix(1700000000, 0)
+
+	for i := 0 ; i < 4 ; i += 1 {
+		if actual := jp.Index(i).EpochTime() ; !actual.Equal(expected) {
+			t.Errorf("Expected element %d to be %v, got %v", i, expected, actual)
+		}
+	}
+
+	if actual := jp.Index(4).EpochTime() ; !actual.IsZero() {
+		t.Errorf("Expected zero time for a string, got %v", actual)
+	}
+}
+
+func TestEpochTimeFarFuture(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`[1e10, 1e13, 10000000000.5, -1e10, 1e300]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	// Both are in 2286, past the reach of int64 nanoseconds.
+	expected := time.Unix(1e10, 0)
+
+	for i := 0 ; i < 2 ; i += 1 {
+		if actual := jp.Index(i).EpochTime() ; !actual.Equal(expected) {
+			t.Errorf("Expected element %d to be %v, got %v", i, expected, actual)
+		}
+	}
+
+	if actual, expected := jp.Index(2).EpochTime(), time.Unix(1e10, 5e8) ; !actual.Equal(expected) {
+		t.Errorf("Expected %v, got %v", expected, actual)
+	}
+
+	if actual, expected := jp.Index(3).EpochTime(), time.Unix(-1e10, 0) ; !actual.Equal(expected) {
+		t.Errorf("Expected %v, got %v", expected, actual)
+	}
+
+	if actual := jp.Index(4).EpochTime() ; !actual.IsZero() {
+		t.Errorf("Expected zero time for an unrepresentable timestamp, got %v", actual)
+	}
+}
+
+func TestTime(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`["2023-11-14T22:13:20Z", "2023-11-14 22:13:20", "yesterday", 1700000000, true]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	expected := time.Unix(1700000000, 0)
+
+	if actual := jp.Index(0).Time() ; !actual.Equal(expected) {
+		t.Errorf("Expected %v, got %v", expected, actual)
+	}
+
+	if actual := jp.Index(1).Time() ; !actual.IsZero() {
+		t.Errorf("Expected the default layout to reject a non-RFC 3339 time, got %v", actual)
+	}
+
+	if actual := jp.Index(1).Time(time.RFC3339, "2006-01-02 15:04:05") ; !actual.Equal(expected) {
+		t.Errorf("Expected %v with a fallback layout, got %v", expected, actual)
+	}
+
+	if actual := jp.Index(3).Time() ; !actual.Equal(expected) {
+		t.Errorf("Expected %v from an epoch number, got %v", expected, actual)
+	}
+}
+
+func TestTimeSlice(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`["2023-11-14T22:13:20Z", 1700000000000, "not a time", null, "2023-11-14T22:13:20.5+01:00"]`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	times := jp.TimeSlice()
+
+	if len(times) != 5 {
+		t.Fatalf("Expected 5 times, got %d", len(times))
+	}
+
+	expected := time.Unix(1700000000, 0)
+
+	if !times[0].Equal(expected) || !times[1].Equal(expected) {
+		t.Errorf("Expected %v for the string and the epoch number, got %v and %v", expected, times[0], times[1])
+	}
+
+	if !times[2].IsZero() || !times[3].IsZero() {
+		t.Errorf("Expected zero times for unconvertible elements, got %v and %v", times[2], times[3])
+	}
+
+	if actual := times[4] ; !actual.Equal(time.Unix(1700000000 - 3600, 5e8)) {
+		t.Errorf("Expected fractional seconds and offsets to be kept, got %v", actual)
+	}
+
+	if times := jp.Index(0).TimeSlice() ; times == nil || len(times) != 0 {
+		t.Errorf("Expected an empty slice for a non-array, got %v", times)
+	}
+}
+
+func TestEpochTimeNumberTypes(t *testing.T) {
+	dec := json.NewDecoder(strings.NewReader(`[1700000000, 1700000000000]`))
+	dec.UseNumber()
+
+	var jp JPath
+
+	if er := jp.ParseDecoder(dec) ; er != nil {
+		t.Fatal(er)
+	}
+
+	expected := time.Unix(1700000000, 0)
+
+	for i, actual := range jp.TimeSlice() {
+		if !actual.Equal(expected) {
+			t.Errorf("Expected json.Number element %d to be %v, got %v", i, expected, actual)
+		}
+	}
+
+	for _, v := range []interface{}{int64(1700000000), uint64(1700000000000), float32(1700000000)} {
+		if actual := (JPath{I: v}).Time() ; !actual.Equal(expected) {
+			t.Errorf("Expected %T to be read as an epoch time, got %v", v, actual)
+		}
+	}
+}
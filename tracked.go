@@ -0,0 +1,83 @@
+package jpath
+
+import (
+	"strconv"
+)
+
+// Tracked is a JPath which remembers how it was reached. Field, Index and Get on a Tracked return
+// Tracked values linked to the one they were called on, so Parent and Sibling can navigate back
+// up again, and, for documents parsed by ParseBytesWithSpans, Span can locate them in the source.
+// Every other JPath method is available through the embedded JPath.
+//
+// Plain JPaths don't carry these links, which keeps navigation cheap and lets a JPath be compared
+// with ==; use WithParents to opt in where they're needed.
+type Tracked struct {
+	JPath
+
+	// parent is the Tracked that Field or Index was called on to produce this one, or nil for a
+	// root.
+	parent *Tracked
+
+	// spans holds the source positions of I and its children, if it was parsed by
+	// ParseBytesWithSpans.
+	spans *spanNode
+}
+
+// WithParents returns a Tracked wrapping the receiver as its root, from which navigation retains
+// parent links.
+func (jp JPath) WithParents() Tracked {
+	return Tracked{JPath: jp}
+}
+
+// Index is like JPath's Index, but the result remembers t as its parent -- even when the element
+// doesn't exist.
+func (t Tracked) Index(i int) Tracked {
+	return Tracked{JPath: t.JPath.Index(i), parent: &t, spans: t.spans.index(i)}
+}
+
+// Field is like JPath's Field, but the result remembers t as its parent -- even when the field
+// doesn't exist.
+func (t Tracked) Field(s string) Tracked {
+	return Tracked{JPath: t.JPath.Field(s), parent: &t, spans: t.spans.field(s)}
+}
+
+// Get is like JPath's Get, but each segment of the path is resolved with Index or Field, so the
+// result is linked back through every intermediate value to t.
+func (t Tracked) Get(path string) Tracked {
+	cur := t
+
+	for _, seg := range splitPath(path) {
+		if _, ok := cur.I.([]interface{}) ; ok {
+			if i, er := strconv.Atoi(seg) ; er == nil && i >= 0 {
+				cur = cur.Index(i)
+			} else {
+				miss := cur
+				cur = Tracked{parent: &miss}
+			}
+		} else {
+			cur = cur.Field(seg)
+		}
+	}
+
+	return cur
+}
+
+// Parent returns the Tracked that Field or Index was called on to produce this one. For a root,
+// a zero-value Tracked is returned.
+func (t Tracked) Parent() Tracked {
+	if t.parent == nil {
+		return Tracked{}
+	}
+
+	return *t.parent
+}
+
+// Sibling returns the named field of this Tracked's parent. If there is no parent, or the parent
+// is not an object, the result wraps a zero-value JPath.
+func (t Tracked) Sibling(name string) Tracked {
+	if t.parent == nil {
+		return Tracked{}
+	}
+
+	return t.parent.Field(name)
+}
@@ -0,0 +1,99 @@
+package jpath
+
+import "testing"
+
+func TestParent(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`{"users": [{"name": "alice"}, {"name": "bob"}]}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	name := jp.WithParents().Field("users").Index(1).Field("name")
+
+	if user := name.Parent() ; user.Field("name").String() != "bob" {
+		t.Errorf("Expected parent to be bob's object, got %#v", user.I)
+	}
+
+	if users := name.Parent().Parent() ; users.Length() != 2 {
+		t.Errorf("Expected grandparent to be the users array, got %#v", users.I)
+	}
+
+	if root := name.Parent().Parent().Parent() ; len(root.Fields()) != 1 {
+		t.Errorf("Expected great-grandparent to be the root, got %#v", root.I)
+	}
+
+	if root := jp.WithParents().Get("users.0.name").Parent().Parent().Parent() ; len(root.Fields()) != 1 {
+		t.Errorf("Expected Get to link back to the root, got %#v", root.I)
+	}
+
+	// A non-numeric segment on an array misses, but must still link back to the root.
+	miss := jp.WithParents().Get("users.x.y")
+
+	if !miss.IsNull() {
+		t.Errorf("Expected a miss, got %#v", miss.I)
+	}
+
+	if users := miss.Parent().Parent() ; users.Length() != 2 {
+		t.Errorf("Expected the miss to link back to the users array, got %#v", users.I)
+	}
+
+	if root := miss.Parent().Parent().Parent() ; len(root.Fields()) != 1 || root.parent != nil {
+		t.Errorf("Expected the miss to link back to the root, got %#v", root.I)
+	}
+
+	if !jp.WithParents().Parent().IsNull() {
+		t.Errorf("Expected root's parent to be a zero-value")
+	}
+}
+
+func TestSibling(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`{"user": {"name": "alice", "email": "alice@example.com"}}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	email := jp.WithParents().Field("user").Field("email")
+
+	if name := email.Sibling("name").String() ; name != "alice" {
+		t.Errorf("Expected alice, got %s", name)
+	}
+
+	if !jp.WithParents().Sibling("user").IsNull() {
+		t.Errorf("Expected zero-value for a JPath without a parent")
+	}
+
+	if !jp.WithParents().Field("user").Field("name").Index(0).Sibling("email").IsNull() {
+		t.Errorf("Expected zero-value when the parent isn't an object")
+	}
+
+	if email := jp.WithParents().Get("user.missing").Sibling("email").String() ; email != "alice@example.com" {
+		t.Errorf("Expected a missing field to still link to its parent")
+	}
+}
+
+func TestUntrackedMisses(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`{"list": [1], "name": "x"}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	misses := []JPath{jp.Field("zzz"), jp.Field("name").Field("x"), jp.Field("list").Index(5), jp.Get("list.9.a")}
+
+	for i, miss := range misses {
+		if miss != (JPath{}) {
+			t.Errorf("Expected miss %d to equal JPath{}, got %#v", i, miss)
+		}
+	}
+
+	// JPath holds nothing but I, so unkeyed literals keep compiling.
+	if str := (JPath{"x"}).String() ; str != "x" {
+		t.Errorf("Expected x, got %q", str)
+	}
+
+	if compiled, er := Compile("list.0") ; er != nil || compiled.Resolve(jp) != jp.Get("list.0") {
+		t.Errorf("Expected a compiled path to resolve to the same JPath as Get")
+	}
+}
@@ -0,0 +1,561 @@
+package jpath
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// deepCopy returns a copy of v in which every object and array has been duplicated, so the
+// result can be modified without affecting v. Scalars are returned as-is.
+func deepCopy(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		ret := make(map[string]interface{}, len(val))
+
+		for k, child := range val {
+			ret[k] = deepCopy(child)
+		}
+
+		return ret
+
+	case []interface{}:
+		ret := make([]interface{}, len(val))
+
+		for i, child := range val {
+			ret[i] = deepCopy(child)
+		}
+
+		return ret
+	}
+
+	return v
+}
+
+// RenameFields returns a new JPath wrapping a deep copy of the underlying object, with any
+// top-level keys present in mapping renamed to their mapped values. Keys not in mapping are left
+// untouched. If the underlying value is not an object, a zero-value JPath is returned.
+//
+// When a renamed key collides with an existing key, the renamed key wins. When several keys are
+// renamed to the same name, the one whose original key sorts last wins.
+func (jp JPath) RenameFields(mapping map[string]string) JPath {
+	obj, ok := jp.I.(map[string]interface{})
+
+	if !ok {
+		return JPath{}
+	}
+
+	ret := make(map[string]interface{}, len(obj))
+	renamed := []string{}
+
+	for k, v := range obj {
+		if _, ok := mapping[k] ; ok {
+			renamed = append(renamed, k)
+		} else {
+			ret[k] = deepCopy(v)
+		}
+	}
+
+	sort.Strings(renamed)
+
+	for _, k := range renamed {
+		ret[mapping[k]] = deepCopy(obj[k])
+	}
+
+	return JPath{I: ret}
+}
+
+// InferSchema returns a new JPath describing the shape of the underlying value. Objects are
+// described by an object mapping each field to its inferred schema, arrays by a one-element
+// array holding the schema of their first element, and everything else by its JSON type name
+// ("null", "boolean", "number", "string"). Arrays whose elements are not all the same JSON type
+// are instead described by a one-element array holding a union note such as "mixed: number|string".
+// Empty arrays are described by an empty array.
+func (jp JPath) InferSchema() JPath {
+	return JPath{I: inferSchema(jp.I)}
+}
+
+func inferSchema(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		ret := make(map[string]interface{}, len(val))
+
+		for k, child := range val {
+			ret[k] = inferSchema(child)
+		}
+
+		return ret
+
+	case []interface{}:
+		if len(val) == 0 {
+			return []interface{}{}
+		}
+
+		kinds := []string{}
+		seen := map[string]bool{}
+
+		for _, child := range val {
+			kind := jsonKind(child)
+
+			if !seen[kind] {
+				seen[kind] = true
+				kinds = append(kinds, kind)
+			}
+		}
+
+		if len(kinds) > 1 {
+			sort.Strings(kinds)
+			return []interface{}{"mixed: " + strings.Join(kinds, "|")}
+		}
+
+		return []interface{}{inferSchema(val[0])}
+	}
+
+	return jsonKind(v)
+}
+
+// ReplaceAll returns a new JPath wrapping a deep copy of the underlying value in which every
+// leaf equal to old is replaced by new. Numbers are compared by value, so ReplaceAll(0, nil)
+// replaces both 0 and 0.0 with null.
+func (jp JPath) ReplaceAll(old, new interface{}) JPath {
+	return JPath{I: replaceAll(jp.I, old, new)}
+}
+
+func replaceAll(v, old, new interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		ret := make(map[string]interface{}, len(val))
+
+		for k, child := range val {
+			ret[k] = replaceAll(child, old, new)
+		}
+
+		return ret
+
+	case []interface{}:
+		ret := make([]interface{}, len(val))
+
+		for i, child := range val {
+			ret[i] = replaceAll(child, old, new)
+		}
+
+		return ret
+	}
+
+	if jsonEqual(v, old) {
+		return deepCopy(new)
+	}
+
+	return v
+}
+
+// Normalize returns a new JPath wrapping a deep copy of the underlying value in which every
+// number, whatever its Go type (int, json.Number, ...), is represented as a float64 -- the same
+// representation ParseBytes produces. Two documents which differ only in how their numbers are
+// encoded, such as 3 and 3.0, normalize to identical trees. Integers too large for a float64 to
+// hold exactly, such as json.Number("12345678901234567891"), are instead kept exact as a
+// json.Number in plain decimal form, so distinct integers never normalize to the same value.
+// Negative zero normalizes to 0. Values with no JSON equivalent are kept as-is.
+func (jp JPath) Normalize() JPath {
+	return JPath{I: normalize(jp.I)}
+}
+
+func normalize(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		ret := make(map[string]interface{}, len(val))
+
+		for k, child := range val {
+			ret[k] = normalize(child)
+		}
+
+		return ret
+
+	case []interface{}:
+		ret := make([]interface{}, len(val))
+
+		for i, child := range val {
+			ret[i] = normalize(child)
+		}
+
+		return ret
+	}
+
+	if num, ok := jsonNumber(v) ; ok {
+		return normalizeNumber(v, num)
+	}
+
+	return v
+}
+
+// normalizeNumber returns num, the float64 value of v, unless v is an integer which num doesn't
+// represent exactly. Such integers are returned as a json.Number holding their exact decimal form.
+func normalizeNumber(v interface{}, num float64) interface{} {
+	// -0 is equal to 0, so it must normalize to the same value.
+	if num == 0 {
+		return float64(0)
+	}
+
+	// Every integer of smaller magnitude is exactly representable.
+	if math.Abs(num) < 1 << 53 || math.IsInf(num, 0) {
+		return num
+	}
+
+	var exact *big.Int
+
+	switch val := v.(type) {
+	case int:
+		exact = big.NewInt(int64(val))
+	case int64:
+		exact = big.NewInt(val)
+	case uint64:
+		exact = new(big.Int).SetUint64(val)
+	case json.Number:
+		// num is finite, so the exponent, and with it the size of the Rat, is bounded.
+		if r, ok := new(big.Rat).SetString(string(val)) ; ok && r.IsInt() {
+			exact = r.Num()
+		}
+	}
+
+	if exact == nil {
+		return num
+	}
+
+	if _, acc := new(big.Float).SetInt(exact).Float64() ; acc == big.Exact {
+		return num
+	}
+
+	return json.Number(exact.String())
+}
+
+// ApplyDefaults returns a new JPath wrapping a deep copy of the underlying value in which every
+// field present in defaults but missing from the receiver has been filled in from defaults.
+// Objects present in both are filled in recursively. Values already present in the receiver are
+// never overwritten -- including explicit nulls, which count as present. Arrays are not merged.
+// A zero-value receiver yields a copy of defaults.
+func (jp JPath) ApplyDefaults(defaults JPath) JPath {
+	return JPath{I: applyDefaults(deepCopy(jp.I), defaults.I)}
+}
+
+func applyDefaults(v, defaults interface{}) interface{} {
+	if v == nil {
+		return deepCopy(defaults)
+	}
+
+	obj, ok := v.(map[string]interface{})
+	defaultObj, defaultOk := defaults.(map[string]interface{})
+
+	if !ok || !defaultOk {
+		return v
+	}
+
+	for k, def := range defaultObj {
+		if cur, present := obj[k] ; !present {
+			obj[k] = deepCopy(def)
+		} else if cur != nil {
+			obj[k] = applyDefaults(cur, def)
+		}
+	}
+
+	return obj
+}
+
+// ToEntries returns a new JPath wrapping an array of [key, value] pairs, one for each field of the
+// underlying object in sorted order, like JavaScript's Object.entries. If the underlying value is
+// not an object, a zero-value JPath is returned.
+func (jp JPath) ToEntries() JPath {
+	obj, ok := jp.I.(map[string]interface{})
+
+	if !ok {
+		return JPath{}
+	}
+
+	ret := make([]interface{}, 0, len(obj))
+
+	for _, k := range jp.SortedFields() {
+		ret = append(ret, []interface{}{k, deepCopy(obj[k])})
+	}
+
+	return JPath{I: ret}
+}
+
+// FromEntries reverses ToEntries, returning a new JPath wrapping an object built from the
+// underlying array of [key, value] pairs, like JavaScript's Object.fromEntries. Keys are coerced
+// with String, and later pairs overwrite earlier ones with the same key. Elements which aren't
+// two-element arrays are skipped. If the underlying value is not an array, a zero-value JPath is
+// returned.
+func (jp JPath) FromEntries() JPath {
+	ary, ok := jp.I.([]interface{})
+
+	if !ok {
+		return JPath{}
+	}
+
+	ret := make(map[string]interface{}, len(ary))
+
+	for _, elem := range ary {
+		if pair, ok := elem.([]interface{}) ; ok && len(pair) == 2 {
+			ret[(JPath{I: pair[0]}).String()] = deepCopy(pair[1])
+		}
+	}
+
+	return JPath{I: ret}
+}
+
+// Pick returns a new JPath wrapping a deep copy of just the values at the given paths, along with
+// the objects and arrays needed to hold them; everything else is dropped. Paths which don't exist
+// are skipped. Arrays along a path keep their elements' positions, so picking "items.2" yields an
+// array of three elements in which the first two are null.
+//
+//     jp.Pick("id", "owner.name")  // {"id": ..., "owner": {"name": ...}}
+func (jp JPath) Pick(paths ...string) JPath {
+	var ret interface{}
+
+	switch jp.I.(type) {
+	case map[string]interface{}:
+		ret = map[string]interface{}{}
+	case []interface{}:
+		ret = []interface{}{}
+	}
+
+	for _, path := range paths {
+		if picked, ok := pick(jp.I, ret, splitPath(path)) ; ok {
+			ret = picked
+		}
+	}
+
+	return JPath{I: ret}
+}
+
+// pick copies the value at segments within src into the corresponding place within dst, creating
+// containers in dst as needed, and returns the new dst. If the path doesn't exist in src, dst is
+// returned untouched and ok is false.
+func pick(src, dst interface{}, segments []string) (ret interface{}, ok bool) {
+	if len(segments) == 0 {
+		return deepCopy(src), true
+	}
+
+	seg := segments[0]
+
+	switch val := src.(type) {
+	case map[string]interface{}:
+		child, present := val[seg]
+		if !present {
+			return dst, false
+		}
+
+		obj, _ := dst.(map[string]interface{})
+
+		picked, ok := pick(child, obj[seg], segments[1:])
+		if !ok {
+			return dst, false
+		}
+
+		if obj == nil {
+			obj = map[string]interface{}{}
+		}
+
+		obj[seg] = picked
+		return obj, true
+
+	case []interface{}:
+		i, er := strconv.Atoi(seg)
+		if er != nil || i < 0 || i >= len(val) {
+			return dst, false
+		}
+
+		ary, _ := dst.([]interface{})
+
+		var existing interface{}
+		if i < len(ary) {
+			existing = ary[i]
+		}
+
+		picked, ok := pick(val[i], existing, segments[1:])
+		if !ok {
+			return dst, false
+		}
+
+		for len(ary) <= i {
+			ary = append(ary, nil)
+		}
+
+		ary[i] = picked
+		return ary, true
+	}
+
+	return dst, false
+}
+
+// Omit returns a new JPath wrapping a deep copy of the underlying value with the values at the
+// given paths removed; everything else is kept. Paths which don't exist are ignored. Omitted
+// array elements are spliced out, and all paths refer to positions in the receiver, so
+// Omit("items.0", "items.1") removes the first two elements.
+func (jp JPath) Omit(paths ...string) JPath {
+	segments := make([][]string, 0, len(paths))
+
+	for _, path := range paths {
+		if segs := splitPath(path) ; len(segs) > 0 {
+			segments = append(segments, segs)
+		}
+	}
+
+	return JPath{I: omit(jp.I, segments)}
+}
+
+// omit returns a deep copy of v without the values at the paths given by segments.
+func omit(v interface{}, segments [][]string) interface{} {
+	if len(segments) == 0 {
+		return deepCopy(v)
+	}
+
+	dropped := map[string]bool{}
+	nested := map[string][][]string{}
+
+	for _, segs := range segments {
+		if len(segs) == 1 {
+			dropped[segs[0]] = true
+		} else {
+			nested[segs[0]] = append(nested[segs[0]], segs[1:])
+		}
+	}
+
+	switch val := v.(type) {
+	case map[string]interface{}:
+		ret := make(map[string]interface{}, len(val))
+
+		for k, child := range val {
+			if !dropped[k] {
+				ret[k] = omit(child, nested[k])
+			}
+		}
+
+		return ret
+
+	case []interface{}:
+		ret := make([]interface{}, 0, len(val))
+
+		for i, child := range val {
+			if seg := strconv.Itoa(i) ; !dropped[seg] {
+				ret = append(ret, omit(child, nested[seg]))
+			}
+		}
+
+		return ret
+	}
+
+	return v
+}
+
+// TruncateStrings returns a new JPath wrapping a deep copy of the underlying value in which every
+// string leaf longer than maxLen runes is cut down to its first maxLen runes followed by "…"
+// (U+2026). Shorter strings, object keys, and non-string values are kept as-is. This is useful
+// for logging documents which may hold very large strings.
+func (jp JPath) TruncateStrings(maxLen int) JPath {
+	return JPath{I: truncateStrings(jp.I, maxLen)}
+}
+
+func truncateStrings(v interface{}, maxLen int) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		ret := make(map[string]interface{}, len(val))
+
+		for k, child := range val {
+			ret[k] = truncateStrings(child, maxLen)
+		}
+
+		return ret
+
+	case []interface{}:
+		ret := make([]interface{}, len(val))
+
+		for i, child := range val {
+			ret[i] = truncateStrings(child, maxLen)
+		}
+
+		return ret
+
+	case string:
+		if maxLen < 0 {
+			maxLen = 0
+		}
+
+		if len(val) <= maxLen || utf8.RuneCountInString(val) <= maxLen {
+			return val
+		}
+
+		runes := 0
+
+		for i := range val {
+			if runes == maxLen {
+				return val[:i] + "…"
+			}
+
+			runes += 1
+		}
+	}
+
+	return v
+}
+
+// Transform returns a new JPath wrapping a deep copy of the underlying value in which every leaf
+// -- every value other than an object or array -- has been replaced by the result of calling fn
+// with its path and value. path is in the dotted form accepted by Get, as with Walk. Empty
+// objects and arrays are kept as-is.
+func (jp JPath) Transform(fn func(path string, v JPath) interface{}) JPath {
+	return JPath{I: transform(jp.I, []string{}, fn)}
+}
+
+func transform(v interface{}, segments []string, fn func(path string, v JPath) interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		ret := make(map[string]interface{}, len(val))
+
+		for k, child := range val {
+			ret[k] = transform(child, append(segments[:len(segments):len(segments)], k), fn)
+		}
+
+		return ret
+
+	case []interface{}:
+		ret := make([]interface{}, len(val))
+
+		for i, child := range val {
+			ret[i] = transform(child, append(segments[:len(segments):len(segments)], strconv.Itoa(i)), fn)
+		}
+
+		return ret
+	}
+
+	return fn(joinPath(segments), JPath{I: v})
+}
+
+// Flatten returns a new JPath wrapping an object which maps the path of every leaf within the
+// underlying value -- every scalar, and every empty object or array -- to a copy of that leaf.
+// Paths are in the dotted form accepted by Get, so {"a": {"b": [1]}} flattens to {"a.b.0": 1}.
+// A scalar receiver flattens to an object with the single key "".
+func (jp JPath) Flatten() JPath {
+	ret := map[string]interface{}{}
+
+	jp.Walk(func(path string, v JPath) {
+		switch val := v.I.(type) {
+		case map[string]interface{}:
+			if len(val) > 0 {
+				return
+			}
+
+		case []interface{}:
+			if len(val) > 0 {
+				return
+			}
+		}
+
+		ret[path] = deepCopy(v.I)
+	})
+
+	return JPath{I: ret}
+}
@@ -0,0 +1,420 @@
+package jpath
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestRenameFields(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`{"user_name": "bob", "e-mail": "bob@example.com", "age": 30}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	renamed := jp.RenameFields(map[string]string{
+		"user_name": "name",
+		"e-mail":    "email",
+	})
+
+	if name := renamed.Field("name").String() ; name != "bob" {
+		t.Errorf("Expected name to be bob, got %s", name)
+	}
+
+	if email := renamed.Field("email").String() ; email != "bob@example.com" {
+		t.Errorf("Expected email to be bob@example.com, got %s", email)
+	}
+
+	if age := renamed.Field("age").Int() ; age != 30 {
+		t.Errorf("Expected age to be 30, got %d", age)
+	}
+
+	if !renamed.Field("user_name").IsNull() {
+		t.Errorf("Expected user_name to be renamed away")
+	}
+
+	if jp.Field("user_name").String() != "bob" {
+		t.Errorf("Original object was modified")
+	}
+}
+
+func TestRenameFieldsCollision(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`{"a": 1, "b": 2, "c": 3}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	renamed := jp.RenameFields(map[string]string{
+		"a": "c",
+		"b": "c",
+	})
+
+	if fields := renamed.Fields() ; len(fields) != 1 {
+		t.Fatalf("Expected 1 field, got %v", fields)
+	}
+
+	if c := renamed.Field("c").Int() ; c != 2 {
+		t.Errorf("Expected c to be 2, got %d", c)
+	}
+
+	if !(JPath{I: "str"}).RenameFields(map[string]string{}).IsNull() {
+		t.Errorf("Expected zero-value for non-object")
+	}
+}
+
+func TestInferSchema(t *testing.T) {
+	var jp JPath
+	jsonBlob := `{
+		"id": 4,
+		"name": "widget",
+		"deleted": false,
+		"parent": null,
+		"tags": ["a", "b"],
+		"mixed": [1, "two"],
+		"owners": [
+			{"name": "alice", "age": 30},
+			{"name": "bob", "age": 41}
+		]
+	}`
+
+	if er := jp.ParseString(jsonBlob) ; er != nil {
+		t.Fatal(er)
+	}
+
+	schema := jp.InferSchema()
+
+	expected := map[string]string{
+		"id":      "number",
+		"name":    "string",
+		"deleted": "boolean",
+		"parent":  "null",
+	}
+
+	for field, kind := range expected {
+		if actual := schema.Field(field).String() ; actual != kind {
+			t.Errorf("Expected %s to be %s, got %s", field, kind, actual)
+		}
+	}
+
+	if tags := schema.Field("tags") ; tags.Length() != 1 || tags.Index(0).String() != "string" {
+		t.Errorf("Expected tags to be [\"string\"], got %#v", tags.I)
+	}
+
+	if mixed := schema.Field("mixed").Index(0).String() ; mixed != "mixed: number|string" {
+		t.Errorf("Expected a union note for mixed, got %s", mixed)
+	}
+
+	owner := schema.Field("owners").Index(0)
+
+	if owner.Field("name").String() != "string" || owner.Field("age").String() != "number" {
+		t.Errorf("Unexpected owner schema %#v", owner.I)
+	}
+}
+
+func TestReplaceAll(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`{"temp": -999, "readings": [1.5, -999.0, 3], "label": "N/A", "name": "probe"}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	nulled := jp.ReplaceAll(-999, nil)
+
+	if !nulled.Field("temp").IsNull() || !nulled.Get("readings.1").IsNull() {
+		t.Errorf("Expected sentinel values to be replaced with null, got %#v", nulled.I)
+	}
+
+	if nulled.Get("readings.0").Float64() != 1.5 || nulled.Get("readings.2").Int() != 3 {
+		t.Errorf("Expected other readings to be untouched, got %#v", nulled.Field("readings").I)
+	}
+
+	if jp.Field("temp").Int() != -999 {
+		t.Errorf("Original value was modified")
+	}
+
+	relabeled := jp.ReplaceAll("N/A", "unknown")
+
+	if label := relabeled.Field("label").String() ; label != "unknown" {
+		t.Errorf("Expected label to be unknown, got %s", label)
+	}
+
+	if name := relabeled.Field("name").String() ; name != "probe" {
+		t.Errorf("Expected name to be untouched, got %s", name)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	var parsed JPath
+
+	if er := parsed.ParseString(`{"count": 3.0, "ids": [1, 2], "name": "x", "none": null}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	built := JPath{I: map[string]interface{}{
+		"count": 3,
+		"ids":   []interface{}{json.Number("1"), int32(2)},
+		"name":  "x",
+		"none":  nil,
+	}}
+
+	if reflect.DeepEqual(parsed.I, built.I) {
+		t.Fatalf("Expected the documents to differ before normalization")
+	}
+
+	if !reflect.DeepEqual(parsed.Normalize().I, built.Normalize().I) {
+		t.Errorf("Expected normalized documents to be equal:\n%#v\n%#v", parsed.Normalize().I, built.Normalize().I)
+	}
+
+	if _, ok := built.I.(map[string]interface{})["count"].(int) ; !ok {
+		t.Errorf("Original value was modified")
+	}
+}
+
+func TestNormalizeBigIntegers(t *testing.T) {
+	a := JPath{I: []interface{}{json.Number("12345678901234567890"), json.Number("1.2345678901234567891e19"), int64(1 << 62 + 1), json.Number("9007199254740992")}}
+	b := JPath{I: []interface{}{json.Number("12345678901234567891"), json.Number("12345678901234567891"), json.Number("4611686018427387905"), 9007199254740992.0}}
+
+	normA, normB := a.Normalize(), b.Normalize()
+
+	if reflect.DeepEqual(normA.Index(0).I, normB.Index(0).I) {
+		t.Errorf("Expected distinct big integers to stay distinct, got %#v for both", normA.Index(0).I)
+	}
+
+	if n, ok := normA.Index(0).I.(json.Number) ; !ok || n != "12345678901234567890" {
+		t.Errorf("Expected an exact json.Number, got %#v", normA.Index(0).I)
+	}
+
+	for i := 1 ; i < 4 ; i += 1 {
+		if !reflect.DeepEqual(normA.Index(i).I, normB.Index(i).I) {
+			t.Errorf("Expected element %d to normalize identically, got %#v and %#v", i, normA.Index(i).I, normB.Index(i).I)
+		}
+	}
+
+	if _, ok := normA.Index(3).I.(float64) ; !ok {
+		t.Errorf("Expected an exactly representable integer to become a float64, got %#v", normA.Index(3).I)
+	}
+}
+
+func TestApplyDefaults(t *testing.T) {
+	var jp, defaults JPath
+
+	if er := jp.ParseString(`{"server": {"port": 9000, "tls": null}, "name": "svc"}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if er := defaults.ParseString(`{"server": {"port": 80, "host": "0.0.0.0", "tls": {"enabled": true}, "timeouts": {"read": 30}}, "debug": false}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	merged := jp.ApplyDefaults(defaults)
+
+	if host := merged.Get("server.host").String() ; host != "0.0.0.0" {
+		t.Errorf("Expected the missing host to be filled in, got %q", host)
+	}
+
+	if read := merged.Get("server.timeouts.read").Int() ; read != 30 {
+		t.Errorf("Expected the missing nested timeout to be filled in, got %d", read)
+	}
+
+	if debug, ok := merged.Field("debug").AsBool() ; !ok || debug {
+		t.Errorf("Expected debug to be filled in as false, got %#v", merged.Field("debug").I)
+	}
+
+	if port := merged.Get("server.port").Int() ; port != 9000 {
+		t.Errorf("Expected the present port to be kept, got %d", port)
+	}
+
+	if tls, present := merged.Field("server").I.(map[string]interface{})["tls"] ; !present || tls != nil {
+		t.Errorf("Expected the present null tls to be kept, got %#v", tls)
+	}
+
+	if !jp.Get("server.host").IsNull() {
+		t.Errorf("Original value was modified")
+	}
+}
+
+func TestEntriesRoundTrip(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`{"b": [1, 2], "a": "x", "c": {"d": null}}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	entries := jp.ToEntries()
+
+	if entries.Length() != 3 {
+		t.Fatalf("Expected 3 entries, got %#v", entries.I)
+	}
+
+	if key, val := entries.Get("0.0").String(), entries.Get("0.1").String() ; key != "a" || val != "x" {
+		t.Errorf(`Expected the first entry to be ["a", "x"], got %#v`, entries.Index(0).I)
+	}
+
+	if !reflect.DeepEqual(entries.FromEntries().I, jp.I) {
+		t.Errorf("Expected a round-trip to reproduce the object, got %#v", entries.FromEntries().I)
+	}
+
+	if !jp.Field("a").ToEntries().IsNull() || !jp.FromEntries().IsNull() {
+		t.Errorf("Expected zero-values for the wrong kinds of input")
+	}
+}
+
+func TestPick(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`{"id": 7, "owner": {"name": "bob", "email": "bob@example.com"}, "items": [{"sku": "a", "qty": 1}, {"sku": "b", "qty": 2}], "secret": "x"}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	picked := jp.Pick("owner.name", "items.1.sku", "missing.path", "items.5")
+
+	expected := map[string]interface{}{
+		"owner": map[string]interface{}{"name": "bob"},
+		"items": []interface{}{nil, map[string]interface{}{"sku": "b"}},
+	}
+
+	if !reflect.DeepEqual(picked.I, expected) {
+		t.Errorf("Expected %#v, got %#v", expected, picked.I)
+	}
+
+	if actual := jp.Pick("missing").I ; !reflect.DeepEqual(actual, map[string]interface{}{}) {
+		t.Errorf("Expected an empty object when nothing is picked, got %#v", actual)
+	}
+
+	picked.Field("owner").I.(map[string]interface{})["name"] = "alice"
+
+	if jp.Get("owner.name").String() != "bob" {
+		t.Errorf("Original value was modified")
+	}
+}
+
+func TestOmit(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`{"id": 7, "owner": {"name": "bob", "password": "hunter2"}, "tags": ["a", "b", "c"], "items": [{"sku": "a", "cost": 1}, {"sku": "b", "cost": 2}]}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	omitted := jp.Omit("owner.password", "tags.0", "tags.1", "items.1.cost", "missing.path", "tags.9")
+
+	expected := map[string]interface{}{
+		"id":    float64(7),
+		"owner": map[string]interface{}{"name": "bob"},
+		"tags":  []interface{}{"c"},
+		"items": []interface{}{
+			map[string]interface{}{"sku": "a", "cost": float64(1)},
+			map[string]interface{}{"sku": "b"},
+		},
+	}
+
+	if !reflect.DeepEqual(omitted.I, expected) {
+		t.Errorf("Expected %#v, got %#v", expected, omitted.I)
+	}
+
+	if jp.Get("owner.password").String() != "hunter2" || jp.Field("tags").Length() != 3 {
+		t.Errorf("Original value was modified")
+	}
+}
+
+func TestTruncateStrings(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`{"body": "abcdefghijklmnop", "name": "short", "greek": "λλλλλλ", "nested": [{"note": "0123456789"}], "n": 12345678901}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	truncated := jp.TruncateStrings(5)
+
+	expected := map[string]string{
+		"body":          "abcde…",
+		"name":          "short",
+		"greek":         "λλλλλ…",
+		"nested.0.note": "01234…",
+	}
+
+	for path, str := range expected {
+		if actual := truncated.Get(path).String() ; actual != str {
+			t.Errorf("Expected %s to be %q, got %q", path, str, actual)
+		}
+	}
+
+	if n := truncated.Field("n").Int64() ; n != 12345678901 {
+		t.Errorf("Expected numbers to be untouched, got %d", n)
+	}
+
+	if jp.Field("body").String() != "abcdefghijklmnop" {
+		t.Errorf("Original value was modified")
+	}
+}
+
+func TestTransform(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`{"a": {"b": 1, "c": "x"}, "list": [2, []], "d": null}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	paths := []string{}
+
+	transformed := jp.Transform(func(path string, v JPath) interface{} {
+		paths = append(paths, path)
+
+		if num, ok := v.AsFloat64() ; ok {
+			return num * 10
+		}
+
+		return v.I
+	})
+
+	expected := map[string]interface{}{
+		"a":    map[string]interface{}{"b": float64(10), "c": "x"},
+		"list": []interface{}{float64(20), []interface{}{}},
+		"d":    nil,
+	}
+
+	if !reflect.DeepEqual(transformed.I, expected) {
+		t.Errorf("Expected %#v, got %#v", expected, transformed.I)
+	}
+
+	if len(paths) != 4 {
+		t.Errorf("Expected fn to be called for the 4 leaves, got %v", paths)
+	}
+
+	if jp.Get("a.b").Int() != 1 {
+		t.Errorf("Original value was modified")
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`{"a": {"b": [1, {"c": null}]}, "d.e": "x", "empty": {}, "list": []}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	expected := map[string]interface{}{
+		"a.b.0":   1.0,
+		"a.b.1.c": nil,
+		`d\.e`:    "x",
+		"empty":   map[string]interface{}{},
+		"list":    []interface{}{},
+	}
+
+	flat := jp.Flatten()
+
+	if !reflect.DeepEqual(flat.I, expected) {
+		t.Errorf("Expected %#v, got %#v", expected, flat.I)
+	}
+
+	for path := range expected {
+		if !reflect.DeepEqual(jp.Get(path).I, flat.Field(path).I) {
+			t.Errorf("Expected %s to resolve to the flattened value, got %#v", path, jp.Get(path).I)
+		}
+	}
+
+	if scalar := jp.Get("a.b.0").Flatten() ; !reflect.DeepEqual(scalar.I, map[string]interface{}{"": 1.0}) {
+		t.Errorf(`Expected a scalar to flatten under "", got %#v`, scalar.I)
+	}
+}
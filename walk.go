@@ -0,0 +1,197 @@
+package jpath
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// Walk calls fn for the underlying value and every value nested within it, depth-first and
+// parents before children. Object fields are visited in sorted order and array elements in
+// index order, so the order of calls is deterministic. path is the location of v relative to
+// the receiver, in the dotted form accepted by Get; the receiver itself has the path "".
+func (jp JPath) Walk(fn func(path string, v JPath)) {
+	jp.WalkFunc(func(path string, v JPath) bool {
+		fn(path, v)
+		return true
+	})
+}
+
+// WalkFunc is like Walk, but fn returns whether to descend into the children of v. Returning
+// false skips v's children (and everything below them), but the walk continues with v's
+// siblings.
+func (jp JPath) WalkFunc(fn func(path string, v JPath) bool) {
+	walk(jp, []string{}, fn)
+}
+
+func walk(jp JPath, segments []string, fn func(path string, v JPath) bool) {
+	if !fn(joinPath(segments), jp) {
+		return
+	}
+
+	switch val := jp.I.(type) {
+	case map[string]interface{}:
+		for _, k := range jp.SortedFields() {
+			walk(jp.Field(k), append(segments[:len(segments):len(segments)], k), fn)
+		}
+
+	case []interface{}:
+		for i := range val {
+			walk(jp.Index(i), append(segments[:len(segments):len(segments)], strconv.Itoa(i)), fn)
+		}
+	}
+}
+
+// AnyKey returns true if name is a key of any object anywhere within the underlying value. The
+// search stops at the first match.
+func (jp JPath) AnyKey(name string) bool {
+	return anyKey(jp.I, name)
+}
+
+func anyKey(v interface{}, name string) bool {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if _, ok := val[name] ; ok {
+			return true
+		}
+
+		for _, child := range val {
+			if anyKey(child, name) {
+				return true
+			}
+		}
+
+	case []interface{}:
+		for _, child := range val {
+			if anyKey(child, name) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// CollectKey returns a JPath for every value stored under the key name in any object within
+// the underlying value, in the order Walk would visit them.
+func (jp JPath) CollectKey(name string) []JPath {
+	ret := []JPath{}
+
+	jp.Walk(func(path string, v JPath) {
+		if obj, ok := v.I.(map[string]interface{}) ; ok {
+			if _, ok := obj[name] ; ok {
+				ret = append(ret, v.Field(name))
+			}
+		}
+	})
+
+	return ret
+}
+
+// HasCycle reports whether the underlying value contains itself -- an object or array which
+// (directly or indirectly) holds a reference to itself. Such values can only be built by hand,
+// by assigning to I, but will send marshaling and recursive methods like Walk into an infinite
+// loop. Values which are merely shared between several places are not cycles.
+func (jp JPath) HasCycle() bool {
+	return hasCycle(jp.I, map[containerID]bool{})
+}
+
+// containerID identifies an object or array by where it lives in memory.
+type containerID struct {
+	ptr uintptr
+	len int
+}
+
+// hasCycle reports whether v contains any of the containers in ancestors, or itself.
+func hasCycle(v interface{}, ancestors map[containerID]bool) bool {
+	var children []interface{}
+
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for _, child := range val {
+			children = append(children, child)
+		}
+
+	case []interface{}:
+		children = val
+
+	default:
+		return false
+	}
+
+	if len(children) == 0 {
+		return false
+	}
+
+	id := containerID{reflect.ValueOf(v).Pointer(), len(children)}
+
+	if ancestors[id] {
+		return true
+	}
+
+	ancestors[id] = true
+	defer delete(ancestors, id)
+
+	for _, child := range children {
+		if hasCycle(child, ancestors) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// StringByteTotal returns the sum of the lengths, in bytes, of every string value within the
+// underlying value. Object keys are not counted.
+func (jp JPath) StringByteTotal() int {
+	ret := 0
+
+	jp.Walk(func(path string, v JPath) {
+		if str, ok := v.I.(string) ; ok {
+			ret += len(str)
+		}
+	})
+
+	return ret
+}
+
+// FirstFailing walks the underlying value in the same order as Walk and returns the path and
+// value of the first node -- including the receiver itself, with the path "" -- for which fn
+// returns true. If fn returns false for every node, "" and a zero-value JPath are returned.
+func (jp JPath) FirstFailing(fn func(path string, v JPath) bool) (string, JPath) {
+	found := false
+	retPath, ret := "", JPath{}
+
+	jp.WalkFunc(func(path string, v JPath) bool {
+		if found {
+			return false
+		}
+
+		if fn(path, v) {
+			found = true
+			retPath, ret = path, v
+		}
+
+		return !found
+	})
+
+	return retPath, ret
+}
+
+// Size returns the number of values within the underlying value, counting every object, array
+// and scalar (including nulls) and the receiver itself, as ParseGuarded's MaxNodes does. So
+// {"a": [1, 2]} has a size of 4.
+func (jp JPath) Size() int {
+	ret := 0
+
+	jp.Walk(func(path string, v JPath) {
+		ret += 1
+	})
+
+	return ret
+}
+
+// SizeDelta returns other's Size minus the receiver's: positive if other is larger, negative if
+// it is smaller.
+func (jp JPath) SizeDelta(other JPath) int {
+	return other.Size() - jp.Size()
+}
@@ -0,0 +1,209 @@
+package jpath
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestAnyKey(t *testing.T) {
+	var jp JPath
+	jsonBlob := `{
+		"user": {
+			"profiles": [
+				{"name": "a"},
+				{"name": "b", "auth": {"password": "hunter2"}}
+			]
+		}
+	}`
+
+	if er := jp.ParseString(jsonBlob) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if !jp.AnyKey("password") {
+		t.Errorf("Expected to find the nested password key")
+	}
+
+	if jp.AnyKey("secret") {
+		t.Errorf("Did not expect to find a secret key")
+	}
+
+	if jp.AnyKey("hunter2") {
+		t.Errorf("Values should not be matched as keys")
+	}
+}
+
+func TestWalk(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`{"b": [1, {"c": true}], "a": "x"}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	paths := []string{}
+
+	jp.Walk(func(path string, v JPath) {
+		paths = append(paths, path)
+	})
+
+	if fmt.Sprint(paths) != "[ a b b.0 b.1 b.1.c]" {
+		t.Errorf("Unexpected walk order %q", paths)
+	}
+}
+
+func TestWalkFunc(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`{"huge": {"x": {"y": 1}, "z": 2}, "small": {"w": 3}, "last": 4}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	paths := []string{}
+
+	jp.WalkFunc(func(path string, v JPath) bool {
+		paths = append(paths, path)
+		return path != "huge"
+	})
+
+	if fmt.Sprint(paths) != "[ huge last small small.w]" {
+		t.Errorf("Unexpected walk order %q", paths)
+	}
+}
+
+func TestCollectKey(t *testing.T) {
+	var jp JPath
+	jsonBlob := `{
+		"id": 1,
+		"children": [
+			{"id": 2, "children": [{"id": 3}]},
+			{"name": "no id"},
+			{"id": 4}
+		]
+	}`
+
+	if er := jp.ParseString(jsonBlob) ; er != nil {
+		t.Fatal(er)
+	}
+
+	ids := []int{}
+
+	for _, id := range jp.CollectKey("id") {
+		ids = append(ids, id.Int())
+	}
+
+	if fmt.Sprint(ids) != "[1 2 3 4]" {
+		t.Errorf("Expected [1 2 3 4], got %v", ids)
+	}
+
+	if found := jp.CollectKey("missing") ; len(found) != 0 {
+		t.Errorf("Expected nothing for an absent key, got %d values", len(found))
+	}
+}
+
+func TestHasCycle(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`{"a": {"b": [1, 2, {"c": 3}]}}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if jp.HasCycle() {
+		t.Errorf("Did not expect a parsed document to have a cycle")
+	}
+
+	shared := map[string]interface{}{"x": 1.0}
+	diamond := JPath{I: []interface{}{shared, shared}}
+
+	if diamond.HasCycle() {
+		t.Errorf("Did not expect a shared value to count as a cycle")
+	}
+
+	child := map[string]interface{}{}
+	root := map[string]interface{}{"child": child}
+	child["list"] = []interface{}{"x", root}
+
+	if !(JPath{I: root}).HasCycle() {
+		t.Errorf("Expected to detect the cycle through child.list")
+	}
+
+	self := []interface{}{1.0, nil}
+	self[1] = self
+
+	if !(JPath{I: self}).HasCycle() {
+		t.Errorf("Expected to detect an array containing itself")
+	}
+}
+
+func TestStringByteTotal(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`{"name": "bob", "tags": ["ab", "λ", ""], "nested": {"note": "hello"}, "n": 12345, "ok": true}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	// "bob" + "ab" + "λ" (two bytes) + "" + "hello"
+	if actual := jp.StringByteTotal() ; actual != 12 {
+		t.Errorf("Expected 12 bytes, got %d", actual)
+	}
+
+	if actual := jp.Field("n").StringByteTotal() ; actual != 0 {
+		t.Errorf("Expected 0 bytes for a number, got %d", actual)
+	}
+}
+
+func TestFirstFailing(t *testing.T) {
+	var jp JPath
+
+	if er := jp.ParseString(`{"b": {"bio": "a very long biography", "name": "ok"}, "a": ["short", "also too long"]}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	tooLong := func(path string, v JPath) bool {
+		str, ok := v.AsString()
+		return ok && len(str) > 8
+	}
+
+	if path, v := jp.FirstFailing(tooLong) ; path != "a.1" || v.String() != "also too long" {
+		t.Errorf(`Expected "a.1" to fail first, got %q (%#v)`, path, v.I)
+	}
+
+	if path, v := jp.Field("b").FirstFailing(tooLong) ; path != "bio" {
+		t.Errorf(`Expected "bio" to fail first, got %q (%#v)`, path, v.I)
+	}
+
+	if path, v := jp.FirstFailing(func(string, JPath) bool { return false }) ; path != "" || !v.IsUndefined() {
+		t.Errorf("Expected no failure, got %q (%#v)", path, v.I)
+	}
+}
+
+func TestSizeDelta(t *testing.T) {
+	var small, large, same JPath
+
+	if er := small.ParseString(`{"a": [1, 2]}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if er := large.ParseString(`{"a": [1, 2, 3], "b": {"c": null}}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if er := same.ParseString(`{"x": ["y", {}]}`) ; er != nil {
+		t.Fatal(er)
+	}
+
+	if size := small.Size() ; size != 4 {
+		t.Errorf("Expected a size of 4, got %d", size)
+	}
+
+	if delta := small.SizeDelta(large) ; delta != 3 {
+		t.Errorf("Expected a delta of 3, got %d", delta)
+	}
+
+	if delta := large.SizeDelta(small) ; delta != -3 {
+		t.Errorf("Expected a delta of -3, got %d", delta)
+	}
+
+	if delta := small.SizeDelta(same) ; delta != 0 {
+		t.Errorf("Expected a delta of 0, got %d", delta)
+	}
+}